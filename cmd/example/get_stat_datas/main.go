@@ -20,9 +20,7 @@ func main() {
 		AppID: AppID,
 	})
 
-	data, err := ac.GetStatsDatas(ctx, estatgo.ParamsGetStatsDatas{
-		MetaGetFlg: "Y",
-	}, []estatgo.StatsDatasSpec{
+	specs := []estatgo.StatsDatasSpec{
 		{
 			StatsDataId:   "0003090287",
 			StartPosition: 1,
@@ -33,10 +31,20 @@ func main() {
 			StartPosition: 1,
 			Limit:         10,
 		},
-	})
+	}
+
+	data, err := ac.GetStatsDatas(ctx, estatgo.ParamsGetStatsDatas{
+		MetaGetFlg: estatgo.FlagYes,
+	}, specs)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	log.Printf("%#v", data)
+	for _, r := range data.Results(specs) {
+		if r.Err != nil {
+			log.Printf("%s: %v", r.Spec.StatsDataId, r.Err)
+			continue
+		}
+		log.Printf("%s: %#v", r.Spec.StatsDataId, r.StatisticalData)
+	}
 }
@@ -33,8 +33,8 @@ func main() {
 				LevelArea: "1",
 			},
 		},
-		OpenSpecified: "1",
-		ProcessMode:   "E",
+		OpenSpecified: estatgo.OpenSpecifiedPublic,
+		ProcessMode:   estatgo.ProcessModeRegister,
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,37 @@
+package core
+
+// 複数の値を 1 つにまとめる関数
+type Aggregation func(values []float64) float64
+
+// 合計
+func AggregateSum(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// 平均
+func AggregateMean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return AggregateSum(values) / float64(len(values))
+}
+
+// 最初の値
+func AggregateFirst(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[0]
+}
+
+// 最後の値
+func AggregateLast(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
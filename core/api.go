@@ -1,29 +1,116 @@
 package core
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// レスポンスの形式
+type Format int
+
+const (
+	// XML 形式 (省略値)
+	FormatXML Format = iota
+
+	// JSON 形式
+	//
+	// e-Stat の JSON は XML を機械的に変換したものなので、xml タグを元に FormatXML と同じ構造体に読み込みます。
+	// レスポンスの構造体は json タグを持たないため、json.Marshal では Go のフィールド名がキーになり、
+	// e-Stat の JSON とは異なる形式になります。
+	FormatJSON
+)
 
 type ApiClient struct {
-	HttpClient   IHttpClient
-	CommonParams CommonParams
+	HttpClient    IHttpClient
+	CommonParams  CommonParams
+	Format        Format
+	WarningPolicy WarningPolicy
+
+	// GetStatsDatasAll の同時実行数。0 以下の場合は DefaultStatsDatasConcurrency を使います。
+	StatsDatasConcurrency int
 }
 
 type IApiClient interface {
 	GetStatsList(ctx context.Context, params ParamsGetStatsList) (*ResponseGetStatsListRoot, error)
+	NewStatsListPager(params ParamsGetStatsList, maxItems int) *StatsListPager
 	GetMetaInfoList(ctx context.Context, params ParamsGetMetaInfoList) (*ResponseGetMetaInfoListRoot, error)
 	GetStatsData(ctx context.Context, params ParamsGetStatsData) (*ResponseGetStatsDataRoot, error)
+	IterateStatsData(ctx context.Context, params ParamsGetStatsData) *StatsDataIterator
 	PostDataset(ctx context.Context, params ParamsPostDataset) (*ResponsePostDatasetRoot, error)
 	RefDataset(ctx context.Context, params ParamsRefDataset) (*ResponseRefDatasetRoot, error)
 	GetDatasetList(ctx context.Context, params ParamsGetDatasetList) (*ResponseGetDatasetListRoot, error)
 	GetDataCatalog(ctx context.Context, params ParamsGetDataCatalog) (*ResponseGetDataCatalogRoot, error)
-	GetStatsDatas(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (*ResponseGetStatsData, error)
+	NewDataCatalogPager(params ParamsGetDataCatalog, maxItems int) *DataCatalogPager
+	GetStatsDatas(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (*ResponseGetStatsDatasRoot, error)
+	GetStatsDatasAll(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) ([]StatsDatasResult, error)
+	GetSimpleStatsData(ctx context.Context, params ParamsGetSimpleStatsData) (*SimpleStatsData, error)
+	GetSimpleStatsDataReader(ctx context.Context, params ParamsGetSimpleStatsData) (io.ReadCloser, error)
+	GetSimpleStatsDatas(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (*SimpleStatsData, error)
+	GetSimpleStatsDatasReader(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (io.ReadCloser, error)
+}
+
+type ApiOption func(*ApiClient)
+
+// レスポンスの形式を指定します。
+func WithFormat(format Format) ApiOption {
+	return func(c *ApiClient) {
+		c.Format = format
+	}
 }
 
 func NewApiClient(
 	httpClient IHttpClient,
 	commonParams CommonParams,
+	opts ...ApiOption,
 ) IApiClient {
-	return &ApiClient{
+	c := &ApiClient{
 		HttpClient:   httpClient,
 		CommonParams: commonParams,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// 共通のパラメータと params を検証する。
+func (c *ApiClient) validate(params interface{ Validate() error }) error {
+	if err := c.CommonParams.Validate(); err != nil {
+		return err
+	}
+	return params.Validate()
+}
+
+// API のパスにレスポンスの形式を付与する。
+func (c *ApiClient) path(path string) string {
+	if c.Format == FormatJSON {
+		return "/json" + path
+	}
+	return path
+}
+
+// レスポンスのルート要素がない場合のエラー
+var errEmptyResponse = errors.New("e-stat: empty response")
+
+// レスポンスの形式に応じて body を読み込む。
+//
+// v はポインタへのポインタで、JSON の null などでルート要素がない場合はエラーを返す。
+func (c *ApiClient) unmarshal(body []byte, v any) error {
+	var err error
+	if c.Format == FormatJSON {
+		err = json.Unmarshal(body, v)
+	} else {
+		err = xml.Unmarshal(body, v)
+	}
+	if err != nil {
+		return err
+	}
+	if rv := reflect.ValueOf(v).Elem(); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return errEmptyResponse
+	}
+	return nil
 }
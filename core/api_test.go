@@ -2,9 +2,11 @@ package core_test
 
 import (
 	"context"
+	"errors"
 	_ "embed"
 	"net/http"
 	"reflect"
+	"testing"
 
 	"github.com/itok01/e-stat-go/core"
 )
@@ -32,6 +34,36 @@ var (
 
 	//go:embed testmock/get_data_catalog.xml
 	responseGetDataCatalog []byte
+
+	//go:embed testmock/get_stats_datas.xml
+	responseGetStatsDatas []byte
+
+	//go:embed testmock/get_simple_stats_data.csv
+	responseGetSimpleStatsData []byte
+
+	//go:embed testmock/get_stats_list.json
+	responseGetStatsListJSON []byte
+
+	//go:embed testmock/get_meta_info.json
+	responseGetMetaInfoListJSON []byte
+
+	//go:embed testmock/get_stat_data.json
+	responseGetStatsDataJSON []byte
+
+	//go:embed testmock/get_stats_datas.json
+	responseGetStatsDatasJSON []byte
+
+	//go:embed testmock/post_dataset.json
+	responsePostDatasetJSON []byte
+
+	//go:embed testmock/ref_dataset.json
+	responseRefDatasetJSON []byte
+
+	//go:embed testmock/get_dataset_list.json
+	responseGetDatasetListJSON []byte
+
+	//go:embed testmock/get_data_catalog.json
+	responseGetDataCatalogJSON []byte
 )
 
 func (hc *mockHttpClient) Get(ctx context.Context, path string, query any) (int, []byte, error) {
@@ -52,6 +84,25 @@ func (hc *mockHttpClient) Get(ctx context.Context, path string, query any) (int,
 		"/getDataCatalog": {
 			reflect.TypeOf(core.ParamsGetDataCatalogRoot{}): responseGetDataCatalog,
 		},
+		"/getSimpleStatsData": {
+			reflect.TypeOf(core.ParamsGetSimpleStatsDataRoot{}): responseGetSimpleStatsData,
+		},
+		"/json/getStatsList": {
+			reflect.TypeOf(core.ParamsGetStatsListRoot{}): responseGetStatsListJSON,
+		},
+		"/json/getMetaInfo": {
+			reflect.TypeOf(core.ParamsGetMetaInfoListRoot{}): responseGetMetaInfoListJSON,
+		},
+		"/json/getStatsData": {
+			reflect.TypeOf(core.ParamsGetStatsDataRoot{}): responseGetStatsDataJSON,
+		},
+		"/json/refDataset": {
+			reflect.TypeOf(core.ParamsRefDatasetRoot{}):     responseRefDatasetJSON,
+			reflect.TypeOf(core.ParamsGetDatasetListRoot{}): responseGetDatasetListJSON,
+		},
+		"/json/getDataCatalog": {
+			reflect.TypeOf(core.ParamsGetDataCatalogRoot{}): responseGetDataCatalogJSON,
+		},
 	}
 
 	if body, ok := resps[path][reflect.ValueOf(query).Type()]; ok {
@@ -66,6 +117,9 @@ func (hc *mockHttpClient) Post(ctx context.Context, path string, data any) (int,
 		"/postDataset": {
 			reflect.TypeOf(core.ParamsPostDatasetRoot{}): responsePostDataset,
 		},
+		"/json/postDataset": {
+			reflect.TypeOf(core.ParamsPostDatasetRoot{}): responsePostDatasetJSON,
+		},
 	}
 
 	if body, ok := resps[path][reflect.ValueOf(data).Type()]; ok {
@@ -76,5 +130,143 @@ func (hc *mockHttpClient) Post(ctx context.Context, path string, data any) (int,
 }
 
 func (hc *mockHttpClient) PostJsonWithQuery(ctx context.Context, path string, query any, structuredData any) (int, []byte, error) {
+	resps := map[string]map[reflect.Type][]byte{
+		"/getStatsDatas": {
+			reflect.TypeOf(&core.ParamsGetStatsDatasRoot{}): responseGetStatsDatas,
+		},
+		"/json/getStatsDatas": {
+			reflect.TypeOf(&core.ParamsGetStatsDatasRoot{}): responseGetStatsDatasJSON,
+		},
+	}
+
+	if body, ok := resps[path][reflect.ValueOf(query).Type()]; ok {
+		return http.StatusOK, body, nil
+	}
+
 	return http.StatusNotFound, nil, nil
 }
+
+func TestFormatJSON(t *testing.T) {
+	ctx := context.Background()
+	hc := mockHttpClient{}
+	xc := core.NewApiClient(&hc, core.CommonParams{})
+	jc := core.NewApiClient(&hc, core.CommonParams{}, core.WithFormat(core.FormatJSON))
+
+	tests := []struct {
+		name string
+		call func(ac core.IApiClient) (any, error)
+	}{
+		{
+			name: "GetStatsList",
+			call: func(ac core.IApiClient) (any, error) {
+				return ac.GetStatsList(ctx, core.ParamsGetStatsList{})
+			},
+		},
+		{
+			name: "GetMetaInfoList",
+			call: func(ac core.IApiClient) (any, error) {
+				return ac.GetMetaInfoList(ctx, core.ParamsGetMetaInfoList{})
+			},
+		},
+		{
+			name: "GetStatsData",
+			call: func(ac core.IApiClient) (any, error) {
+				return ac.GetStatsData(ctx, core.ParamsGetStatsData{})
+			},
+		},
+		{
+			name: "GetStatsDatas",
+			call: func(ac core.IApiClient) (any, error) {
+				data, err := ac.GetStatsDatas(ctx, core.ParamsGetStatsDatas{}, nil)
+				if errors.Is(err, core.ErrPartialError) {
+					err = nil
+				}
+				return data, err
+			},
+		},
+		{
+			name: "PostDataset",
+			call: func(ac core.IApiClient) (any, error) {
+				return ac.PostDataset(ctx, core.ParamsPostDataset{})
+			},
+		},
+		{
+			name: "RefDataset",
+			call: func(ac core.IApiClient) (any, error) {
+				return ac.RefDataset(ctx, core.ParamsRefDataset{})
+			},
+		},
+		{
+			name: "GetDatasetList",
+			call: func(ac core.IApiClient) (any, error) {
+				return ac.GetDatasetList(ctx, core.ParamsGetDatasetList{})
+			},
+		},
+		{
+			name: "GetDataCatalog",
+			call: func(ac core.IApiClient) (any, error) {
+				return ac.GetDataCatalog(ctx, core.ParamsGetDataCatalog{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := tt.call(xc)
+			if err != nil {
+				t.Fatalf("%s() XML error = %v", tt.name, err)
+			}
+			got, err := tt.call(jc)
+			if err != nil {
+				t.Fatalf("%s() JSON error = %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s() JSON = %v, want %v", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestFormatJSONNull(t *testing.T) {
+	ctx := context.Background()
+	hc := newCountingHttpClient()
+	hc.resp = func(path string) (int, []byte) {
+		return http.StatusOK, []byte("null")
+	}
+	ac := core.NewApiClient(hc, core.CommonParams{}, core.WithFormat(core.FormatJSON))
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "GetStatsList",
+			call: func() error {
+				_, err := ac.GetStatsList(ctx, core.ParamsGetStatsList{})
+				return err
+			},
+		},
+		{
+			name: "GetStatsData",
+			call: func() error {
+				_, err := ac.GetStatsData(ctx, core.ParamsGetStatsData{})
+				return err
+			},
+		},
+		{
+			name: "GetStatsDatas",
+			call: func() error {
+				_, err := ac.GetStatsDatas(ctx, core.ParamsGetStatsDatas{}, nil)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err == nil {
+				t.Errorf("%s() error = nil", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,483 @@
+package core
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	querystring "github.com/google/go-querystring/query"
+)
+
+// キャッシュに保存するレスポンス
+type CacheEntry struct {
+	// リクエストのパスと、appId を除いたクエリ
+	Path  string
+	Query string
+
+	StatusCode int
+	Body       []byte
+
+	StoredAt time.Time
+
+	// 有効期限。ゼロ値の場合は期限なし
+	ExpiresAt time.Time
+}
+
+// 有効期限が切れているかどうか
+func (e CacheEntry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// レスポンスの保存先
+//
+// NewMemoryCache、NewFileCache のほか、独自の保存先を実装して使えます。
+// 複数の goroutine から同時に呼ばれます。
+type CacheBackend interface {
+	// key のエントリを返します。ない場合は false を返します。
+	Get(key string) (CacheEntry, bool, error)
+
+	Set(key string, entry CacheEntry) error
+
+	Delete(key string) error
+}
+
+// エントリを列挙できる CacheBackend
+//
+// FreshnessWatcher が更新された統計表のエントリを探すのに使います。
+type CacheRanger interface {
+	// f が false を返すまで、すべてのエントリについて f を呼びます。
+	Range(f func(key string, entry CacheEntry) bool) error
+}
+
+// キャッシュの使い方 (WithCacheMode)
+type CacheMode int
+
+const (
+	// キャッシュを読み書きする (省略値)
+	CacheDefault CacheMode = iota
+
+	// キャッシュを読まずにリクエストし、結果を保存する
+	CacheRefresh
+
+	// キャッシュを読み書きしない
+	CacheBypass
+)
+
+type cacheModeKey struct{}
+
+// ctx を使うリクエストのキャッシュの使い方を指定します。
+//
+//	ctx := core.WithCacheMode(ctx, core.CacheRefresh)
+//	data, err := ac.GetMetaInfoList(ctx, params)
+func WithCacheMode(ctx context.Context, mode CacheMode) context.Context {
+	return context.WithValue(ctx, cacheModeKey{}, mode)
+}
+
+func cacheModeOf(ctx context.Context) CacheMode {
+	mode, _ := ctx.Value(cacheModeKey{}).(CacheMode)
+	return mode
+}
+
+// CachingClient のヒット数などの統計
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+	Stores int64
+
+	// 保存先のエラーの数。エラーの場合はキャッシュなしでリクエストします。
+	Errors int64
+}
+
+// レスポンスをキャッシュする IHttpClient
+//
+// Get と PostJsonWithQuery のレスポンスを、パスと appId を除いたクエリ (と JSON のボディ) をキーに保存します。
+// データを変更する Post はキャッシュしません。
+// RESULT の STATUS が正常終了のレスポンスのみキャッシュし、該当データがない場合は WithNoDataTTL に従います。
+//
+// IHttpStreamClient を実装しないため、ApiClient は CSV 形式の取得にも Get を使い、その結果もキャッシュされます。
+type CachingClient struct {
+	client  IHttpClient
+	backend CacheBackend
+
+	ttl          time.Duration
+	endpointTTL  map[string]time.Duration
+	noDataTTL    time.Duration
+	maxEntrySize int
+	logger       *slog.Logger
+
+	hits, misses, stores, errs int64
+}
+
+type CacheOption func(*CachingClient)
+
+// 有効期限を指定します。0 の場合は期限なし (省略値) です。
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *CachingClient) {
+		c.ttl = ttl
+	}
+}
+
+// endpoint ("/getStatsData" など) の有効期限を指定します。
+//
+// JSON 形式のパス ("/json/getStatsData") にも適用されます。負の場合はキャッシュしません。
+func WithEndpointTTL(endpoint string, ttl time.Duration) CacheOption {
+	return func(c *CachingClient) {
+		c.endpointTTL[endpoint] = ttl
+	}
+}
+
+// 該当データがない (STATUS が 1) レスポンスの有効期限を指定します。
+//
+// 0 (省略値) の場合はキャッシュしません。統計表の公開前などで一時的にデータがない場合に
+// 古い結果を返し続けないよう、通常の有効期限より短く指定して下さい。
+func WithNoDataTTL(ttl time.Duration) CacheOption {
+	return func(c *CachingClient) {
+		c.noDataTTL = ttl
+	}
+}
+
+// size バイトより大きいレスポンスをキャッシュしません。
+func WithMaxEntrySize(size int) CacheOption {
+	return func(c *CachingClient) {
+		c.maxEntrySize = size
+	}
+}
+
+// キャッシュから返したレスポンスと保存先のエラーのログの出力先を指定します。
+//
+// キャッシュから返したレスポンスは、HttpClient (WithSlogLogger) のリクエストのログと同じ属性に
+// cache=hit を加えて slog.LevelInfo で出力します。保存先のエラーは slog.LevelWarn で出力します。
+func WithCacheLogger(logger *slog.Logger) CacheOption {
+	return func(c *CachingClient) {
+		c.logger = logger
+	}
+}
+
+// client のレスポンスを backend にキャッシュする CachingClient を返します。
+//
+//	hc := core.NewCachingClient(core.New(), core.NewFileCache(dir, 1<<30),
+//		core.WithCacheTTL(24*time.Hour),
+//		core.WithEndpointTTL("/getStatsList", time.Hour))
+//	ac := core.NewApiClient(hc, core.CommonParams{AppID: appID})
+func NewCachingClient(client IHttpClient, backend CacheBackend, opts ...CacheOption) *CachingClient {
+	c := &CachingClient{
+		client:      client,
+		backend:     backend,
+		endpointTTL: map[string]time.Duration{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// 統計を返します。
+func (c *CachingClient) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+		Stores: atomic.LoadInt64(&c.stores),
+		Errors: atomic.LoadInt64(&c.errs),
+	}
+}
+
+func (c *CachingClient) Get(ctx context.Context, path string, query any) (int, []byte, error) {
+	q, err := CanonicalQuery(query)
+	if err != nil {
+		return 0, nil, err
+	}
+	return c.do(ctx, http.MethodGet, path, q, "", func() (int, []byte, error) {
+		return c.client.Get(ctx, path, query)
+	})
+}
+
+func (c *CachingClient) Post(ctx context.Context, path string, data any) (int, []byte, error) {
+	return c.client.Post(ctx, path, data)
+}
+
+func (c *CachingClient) PostJsonWithQuery(ctx context.Context, path string, query any, structuredData any) (int, []byte, error) {
+	q, err := CanonicalQuery(query)
+	if err != nil {
+		return 0, nil, err
+	}
+	body, err := json.Marshal(structuredData)
+	if err != nil {
+		return 0, nil, err
+	}
+	return c.do(ctx, http.MethodPost, path, q, string(body), func() (int, []byte, error) {
+		return c.client.PostJsonWithQuery(ctx, path, query, structuredData)
+	})
+}
+
+// Get のキャッシュを削除します。
+func (c *CachingClient) Invalidate(path string, query any) error {
+	q, err := CanonicalQuery(query)
+	if err != nil {
+		return err
+	}
+	return c.backend.Delete(CacheKey(path, q, ""))
+}
+
+// PostJsonWithQuery のキャッシュを削除します。
+func (c *CachingClient) InvalidatePostJsonWithQuery(path string, query any, structuredData any) error {
+	q, err := CanonicalQuery(query)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(structuredData)
+	if err != nil {
+		return err
+	}
+	return c.backend.Delete(CacheKey(path, q, string(body)))
+}
+
+func (c *CachingClient) do(ctx context.Context, method string, path string, query string, body string, fetch func() (int, []byte, error)) (int, []byte, error) {
+	ttl, ok := c.endpointTTL[endpointOf(path)]
+	if !ok {
+		ttl = c.ttl
+	}
+	mode := cacheModeOf(ctx)
+	if ttl < 0 || mode == CacheBypass {
+		return fetch()
+	}
+
+	key := CacheKey(path, query, body)
+	if mode != CacheRefresh {
+		entry, ok, err := c.backend.Get(key)
+		if err != nil {
+			atomic.AddInt64(&c.errs, 1)
+			c.logError(ctx, path, err)
+		}
+		if ok && !entry.Expired(time.Now()) {
+			atomic.AddInt64(&c.hits, 1)
+			c.logHit(ctx, method, path, query, entry)
+			return entry.StatusCode, entry.Body, nil
+		}
+	}
+	atomic.AddInt64(&c.misses, 1)
+
+	statusCode, b, err := fetch()
+	if err != nil || (c.maxEntrySize > 0 && len(b) > c.maxEntrySize) {
+		return statusCode, b, err
+	}
+	ttl, ok = c.storeTTL(path, ttl, statusCode, b)
+	if !ok {
+		return statusCode, b, nil
+	}
+
+	entry := CacheEntry{Path: path, Query: query, StatusCode: statusCode, Body: b, StoredAt: time.Now()}
+	if ttl > 0 {
+		entry.ExpiresAt = entry.StoredAt.Add(ttl)
+	}
+	if err := c.backend.Set(key, entry); err != nil {
+		atomic.AddInt64(&c.errs, 1)
+		c.logError(ctx, path, err)
+	} else {
+		atomic.AddInt64(&c.stores, 1)
+	}
+	return statusCode, b, nil
+}
+
+func (c *CachingClient) logHit(ctx context.Context, method string, path string, query string, entry CacheEntry) {
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelInfo) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("endpoint", path),
+		slog.String("query", query),
+		slog.Int("status", entry.StatusCode),
+		slog.Int("bytes", len(entry.Body)),
+	}
+	if status, ok := resultStatusOf(entry.Body); ok {
+		attrs = append(attrs, slog.Int("result_status", status))
+	}
+	attrs = append(attrs, slog.String("cache", "hit"))
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "e-stat request", attrs...)
+}
+
+func (c *CachingClient) logError(ctx context.Context, path string, err error) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelWarn, "e-stat cache error", slog.String("endpoint", path), slog.String("error", err.Error()))
+}
+
+// パス、クエリ、JSON のボディからキャッシュのキーを作ります。
+func CacheKey(path string, query string, body string) string {
+	key := path + "?" + query
+	if body != "" {
+		key += "\n" + body
+	}
+	return key
+}
+
+// appId を除き、キーの順に並べたクエリを返します。
+func CanonicalQuery(query any) (string, error) {
+	values, err := querystring.Values(query)
+	if err != nil {
+		return "", err
+	}
+	values.Del("appId")
+	return values.Encode(), nil
+}
+
+// "/json/getStatsData" などのパスから "/getStatsData" を返す。
+func endpointOf(path string) string {
+	return strings.TrimPrefix(path, "/json")
+}
+
+// SectionHeaderFlg によって RESULT を含まないレスポンスを返すエンドポイント
+var headerlessEndpoints = map[string]bool{
+	"/getSimpleStatsData":  true,
+	"/getSimpleStatsDatas": true,
+}
+
+var resultStatusPattern = regexp.MustCompile(`<STATUS>(\d+)</STATUS>|"STATUS"\s*[:,]\s*"?(\d+)`)
+
+// レスポンスを保存する場合の有効期限を返す。保存しない場合は false を返す。
+//
+// 一部にエラーがある (STATUS が 2) 場合は一時的な失敗の可能性があるため、警告もキャッシュしない。
+// STATUS がないレスポンスは、ヘッダなしの CSV 形式を返すエンドポイント以外では
+// HTML のエラーページや途中で切れたレスポンスの可能性があるため、キャッシュしない。
+func (c *CachingClient) storeTTL(path string, ttl time.Duration, statusCode int, body []byte) (time.Duration, bool) {
+	if !isSuccessHTTPStatus(statusCode) {
+		return 0, false
+	}
+
+	status, ok := resultStatusOf(body)
+	switch {
+	case !ok:
+		return ttl, headerlessEndpoints[endpointOf(path)]
+	case status == ResultStatusOK:
+		return ttl, true
+	case status == ResultStatusNoData && c.noDataTTL > 0:
+		if ttl > 0 && ttl < c.noDataTTL {
+			return ttl, true
+		}
+		return c.noDataTTL, true
+	}
+	return 0, false
+}
+
+// レスポンスの RESULT の STATUS を返す。STATUS がない場合は false を返す。
+func resultStatusOf(body []byte) (int, bool) {
+	// STATUS は先頭の RESULT にある
+	head := body
+	if len(head) > resultHeadSize {
+		head = head[:resultHeadSize]
+	}
+	m := resultStatusPattern.FindSubmatch(head)
+	if m == nil {
+		return 0, false
+	}
+	s := m[1]
+	if s == nil {
+		s = m[2]
+	}
+	status, err := strconv.Atoi(string(s))
+	return status, err == nil
+}
+
+// メモリ上の LRU キャッシュ
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	size       int64
+	items      map[string]*list.Element
+	order      *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// 最大 maxEntries 件、レスポンスの合計が最大 maxBytes バイトの MemoryCache を返します。
+//
+// 0 以下の場合は制限しません。上限を超えると最も長く使われていないエントリから削除します。
+func NewMemoryCache(maxEntries int, maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		items:      map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+func (m *MemoryCache) Get(key string) (CacheEntry, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.items[key]
+	if !ok {
+		return CacheEntry{}, false, nil
+	}
+	m.order.MoveToFront(e)
+	return e.Value.(*memoryCacheItem).entry, true, nil
+}
+
+func (m *MemoryCache) Set(key string, entry CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.items[key]; ok {
+		m.remove(e)
+	}
+	m.items[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	m.size += int64(len(entry.Body))
+
+	for m.order.Len() > 0 && ((m.maxEntries > 0 && m.order.Len() > m.maxEntries) || (m.maxBytes > 0 && m.size > m.maxBytes)) {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+func (m *MemoryCache) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.items[key]; ok {
+		m.remove(e)
+	}
+	return nil
+}
+
+func (m *MemoryCache) Range(f func(key string, entry CacheEntry) bool) error {
+	m.mu.Lock()
+	items := make([]*memoryCacheItem, 0, m.order.Len())
+	for e := m.order.Front(); e != nil; e = e.Next() {
+		items = append(items, e.Value.(*memoryCacheItem))
+	}
+	m.mu.Unlock()
+
+	for _, item := range items {
+		if !f(item.key, item.entry) {
+			break
+		}
+	}
+	return nil
+}
+
+// エントリの数を返します。
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.order.Len()
+}
+
+func (m *MemoryCache) remove(e *list.Element) {
+	item := m.order.Remove(e).(*memoryCacheItem)
+	delete(m.items, item.key)
+	m.size -= int64(len(item.entry.Body))
+}
@@ -0,0 +1,318 @@
+package core_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+)
+
+// リクエストの回数を数える IHttpClient
+//
+// resp が nil の場合は mockHttpClient のレスポンスを返す。
+type countingHttpClient struct {
+	estattest.ClientFunc
+
+	mu    sync.Mutex
+	calls map[string]int
+	resp  func(path string) (int, []byte)
+}
+
+func newCountingHttpClient() *countingHttpClient {
+	hc := &countingHttpClient{calls: map[string]int{}}
+	hc.ClientFunc = hc.do
+	return hc
+}
+
+func (hc *countingHttpClient) count(path string) int {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	return hc.calls[path]
+}
+
+func (hc *countingHttpClient) do(ctx context.Context, r estattest.ClientRequest) (int, []byte, error) {
+	hc.mu.Lock()
+	hc.calls[r.Path]++
+	hc.mu.Unlock()
+	if hc.resp != nil {
+		statusCode, body := hc.resp(r.Path)
+		return statusCode, body, nil
+	}
+
+	mock := &mockHttpClient{}
+	switch {
+	case r.Body != nil:
+		return mock.PostJsonWithQuery(ctx, r.Path, r.Query, r.Body)
+	case r.Method == http.MethodPost:
+		return mock.Post(ctx, r.Path, r.Query)
+	}
+	return mock.Get(ctx, r.Path, r.Query)
+}
+
+func TestCachingClient(t *testing.T) {
+	ctx := context.Background()
+	hc := newCountingHttpClient()
+	cc := core.NewCachingClient(hc, core.NewMemoryCache(0, 0))
+
+	params := core.ParamsGetMetaInfoList{StatsDataId: "0003109741"}
+	for _, appID := range []string{"a", "b"} {
+		ac := core.NewApiClient(cc, core.CommonParams{AppID: appID})
+		data, err := ac.GetMetaInfoList(ctx, params)
+		if err != nil {
+			t.Fatalf("GetMetaInfoList() error = %v", err)
+		}
+		if data.DataList.Table.ID != "0003109741" {
+			t.Errorf("TABLE_INF id = %q", data.DataList.Table.ID)
+		}
+	}
+	if got := hc.count("/getMetaInfo"); got != 1 {
+		t.Errorf("requests = %d, want 1 (appId must not be part of the key)", got)
+	}
+
+	ac := core.NewApiClient(cc, core.CommonParams{})
+	if _, err := ac.GetMetaInfoList(ctx, core.ParamsGetMetaInfoList{StatsDataId: "0003109741", ExplanationGetFlg: core.FlagNo}); err != nil {
+		t.Fatal(err)
+	}
+	if got := hc.count("/getMetaInfo"); got != 2 {
+		t.Errorf("requests = %d, want 2 (different params)", got)
+	}
+
+	if _, err := ac.GetMetaInfoList(core.WithCacheMode(ctx, core.CacheBypass), params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ac.GetMetaInfoList(core.WithCacheMode(ctx, core.CacheRefresh), params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ac.GetMetaInfoList(ctx, params); err != nil {
+		t.Fatal(err)
+	}
+	if got := hc.count("/getMetaInfo"); got != 4 {
+		t.Errorf("requests = %d, want 4", got)
+	}
+
+	// POST はキャッシュしない
+	for i := 0; i < 2; i++ {
+		if _, err := ac.PostDataset(ctx, core.ParamsPostDataset{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := hc.count("/postDataset"); got != 2 {
+		t.Errorf("postDataset requests = %d, want 2", got)
+	}
+
+	want := core.CacheStats{Hits: 2, Misses: 3, Stores: 3}
+	if got := cc.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCachingClientTTL(t *testing.T) {
+	ctx := context.Background()
+	hc := newCountingHttpClient()
+	cc := core.NewCachingClient(hc, core.NewMemoryCache(0, 0),
+		core.WithCacheTTL(time.Hour),
+		core.WithEndpointTTL("/getStatsList", time.Millisecond),
+		core.WithEndpointTTL("/getStatsData", -1),
+	)
+	ac := core.NewApiClient(cc, core.CommonParams{}, core.WithFormat(core.FormatJSON))
+
+	for i := 0; i < 2; i++ {
+		if _, err := ac.GetStatsList(ctx, core.ParamsGetStatsList{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ac.GetStatsData(ctx, core.ParamsGetStatsData{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ac.GetMetaInfoList(ctx, core.ParamsGetMetaInfoList{}); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	for path, want := range map[string]int{"/json/getStatsList": 2, "/json/getStatsData": 2, "/json/getMetaInfo": 1} {
+		if got := hc.count(path); got != want {
+			t.Errorf("%s requests = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestCachingClientErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+	}{
+		{"HTTPStatus", http.StatusServiceUnavailable, ""},
+		{"ResultStatusXML", http.StatusOK, `<GET_META_INFO><RESULT><STATUS>100</STATUS></RESULT></GET_META_INFO>`},
+		{"ResultStatusJSON", http.StatusOK, `{"GET_META_INFO":{"RESULT":{"STATUS":101}}}`},
+		{"ResultStatusCSV", http.StatusOK, "\"RESULT\"\n\"STATUS\",\"103\"\n"},
+		{"NoData", http.StatusOK, `<GET_META_INFO><RESULT><STATUS>1</STATUS></RESULT></GET_META_INFO>`},
+		{"PartialError", http.StatusOK, `<GET_STATS_DATAS><RESULT><STATUS>2</STATUS></RESULT></GET_STATS_DATAS>`},
+		{"NoStatusHTML", http.StatusOK, `<html><body>メンテナンス中です</body></html>`},
+		{"Truncated", http.StatusOK, `<GET_META_INFO><RESULT><STA`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := newCountingHttpClient()
+			hc.resp = func(string) (int, []byte) {
+				return tt.statusCode, []byte(tt.body)
+			}
+			cc := core.NewCachingClient(hc, core.NewMemoryCache(0, 0))
+			for i := 0; i < 2; i++ {
+				cc.Get(context.Background(), "/getMetaInfo", core.ParamsGetMetaInfoListRoot{})
+			}
+			if got := hc.count("/getMetaInfo"); got != 2 {
+				t.Errorf("requests = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestCachingClientNoDataTTL(t *testing.T) {
+	hc := newCountingHttpClient()
+	hc.resp = func(string) (int, []byte) {
+		return http.StatusOK, []byte(`<GET_META_INFO><RESULT><STATUS>1</STATUS></RESULT></GET_META_INFO>`)
+	}
+	cc := core.NewCachingClient(hc, core.NewMemoryCache(0, 0), core.WithNoDataTTL(10*time.Millisecond))
+
+	get := func() {
+		cc.Get(context.Background(), "/getMetaInfo", core.ParamsGetMetaInfoListRoot{})
+	}
+	get()
+	get()
+	if got := hc.count("/getMetaInfo"); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	time.Sleep(20 * time.Millisecond)
+	get()
+	if got := hc.count("/getMetaInfo"); got != 2 {
+		t.Errorf("requests = %d, want 2 (no data entry must expire)", got)
+	}
+}
+
+func TestCachingClientHeaderless(t *testing.T) {
+	hc := newCountingHttpClient()
+	hc.resp = func(string) (int, []byte) {
+		return http.StatusOK, []byte("\"tab_code\",\"value\"\n\"11\",\"1\"\n")
+	}
+	cc := core.NewCachingClient(hc, core.NewMemoryCache(0, 0))
+
+	// SectionHeaderFlg=2 の CSV 形式には STATUS がないが、キャッシュする
+	for i := 0; i < 2; i++ {
+		cc.Get(context.Background(), "/getSimpleStatsData", core.ParamsGetSimpleStatsData{SectionHeaderFlg: "2"})
+	}
+	if got := hc.count("/getSimpleStatsData"); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestCachingClientInvalidate(t *testing.T) {
+	ctx := context.Background()
+	hc := newCountingHttpClient()
+	hc.resp = func(string) (int, []byte) {
+		return http.StatusOK, []byte(`<RESULT><STATUS>0</STATUS></RESULT>`)
+	}
+	cc := core.NewCachingClient(hc, core.NewMemoryCache(0, 0))
+
+	query := core.ParamsGetMetaInfoListRoot{}
+	specs := []core.StatsDatasSpec{{StatsDataId: "0003109741"}}
+	fetch := func() {
+		cc.Get(ctx, "/getMetaInfo", query)
+		cc.PostJsonWithQuery(ctx, "/getStatsDatas", query, specs)
+	}
+
+	fetch()
+	if err := cc.Invalidate("/getMetaInfo", query); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	fetch()
+	if got := hc.count("/getMetaInfo"); got != 2 {
+		t.Errorf("getMetaInfo requests = %d, want 2", got)
+	}
+	if got := hc.count("/getStatsDatas"); got != 1 {
+		t.Errorf("getStatsDatas requests = %d, want 1", got)
+	}
+
+	if err := cc.InvalidatePostJsonWithQuery("/getStatsDatas", query, specs); err != nil {
+		t.Fatalf("InvalidatePostJsonWithQuery() error = %v", err)
+	}
+	fetch()
+	if got := hc.count("/getStatsDatas"); got != 2 {
+		t.Errorf("getStatsDatas requests = %d, want 2", got)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	c := core.NewMemoryCache(2, 10)
+	c.Set("a", core.CacheEntry{Body: []byte("aaa")})
+	c.Set("b", core.CacheEntry{Body: []byte("bbb")})
+	c.Get("a")
+	c.Set("c", core.CacheEntry{Body: []byte("ccc")})
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok, _ := c.Get(key); ok != want {
+			t.Errorf("Get(%q) ok = %v, want %v", key, ok, want)
+		}
+	}
+
+	// 合計の上限
+	c.Set("d", core.CacheEntry{Body: []byte("dddddddd")})
+	if got := c.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+	if _, ok, _ := c.Get("d"); !ok {
+		t.Error(`Get("d") ok = false`)
+	}
+
+	c.Delete("d")
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+
+	entry := core.CacheEntry{Path: "/getMetaInfo", Query: "statsDataId=1", StatusCode: http.StatusOK, Body: []byte("<xml/>")}
+	if err := core.NewFileCache(dir, 0).Set("key", entry); err != nil {
+		t.Fatal(err)
+	}
+
+	c := core.NewFileCache(dir, 0)
+	got, ok, err := c.Get("key")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v", ok, err)
+	}
+	if got.Path != entry.Path || got.Query != entry.Query || string(got.Body) != string(entry.Body) {
+		t.Errorf("Get() = %+v, want %+v", got, entry)
+	}
+
+	if err := c.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := c.Get("key"); ok {
+		t.Error("Get() after Delete() ok = true")
+	}
+	if err := c.Delete("key"); err != nil {
+		t.Errorf("Delete() missing key error = %v", err)
+	}
+
+	// 古いエントリから削除する
+	small := core.NewFileCache(dir, 400)
+	for _, key := range []string{"a", "b", "c"} {
+		if err := small.Set(key, core.CacheEntry{Body: make([]byte, 100)}); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok, _ := small.Get("a"); ok {
+		t.Error(`Get("a") ok = true, want evicted`)
+	}
+	if _, ok, _ := small.Get("c"); !ok {
+		t.Error(`Get("c") ok = false`)
+	}
+}
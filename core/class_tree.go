@@ -0,0 +1,175 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ClassTree.Path の区切り文字
+const ClassPathSeparator = " > "
+
+// 分類事項の階層のノード
+type ClassNode struct {
+	ClassObjClass
+
+	// ルートは 0
+	Depth int
+
+	Parent   *ClassNode
+	Children []*ClassNode
+}
+
+// 階層レベルを返します。level 属性がない場合は Depth + 1 を返します。
+func (n *ClassNode) LevelNumber() int {
+	if level, err := strconv.Atoi(n.Level); err == nil {
+		return level
+	}
+	return n.Depth + 1
+}
+
+// 分類事項の階層
+//
+// parentCode を持たない分類、または parentCode の分類が含まれていない分類はルートになります。
+type ClassTree struct {
+	Roots []*ClassNode
+
+	nodes map[string]*ClassNode
+	order []*ClassNode
+}
+
+// level と parentCode から分類事項の階層を作ります。
+func (o ClassObj) Tree() *ClassTree {
+	t := &ClassTree{
+		nodes: make(map[string]*ClassNode, len(o.Class)),
+		order: make([]*ClassNode, len(o.Class)),
+	}
+	for i, c := range o.Class {
+		n := &ClassNode{ClassObjClass: c}
+		t.order[i] = n
+		if _, ok := t.nodes[c.Code]; !ok {
+			t.nodes[c.Code] = n
+		}
+	}
+
+	for _, n := range t.order {
+		parent, ok := t.nodes[n.ParentCode]
+		if n.ParentCode == "" || !ok || parent == n || parent.hasAncestor(n) {
+			t.Roots = append(t.Roots, n)
+			continue
+		}
+		n.Parent = parent
+		parent.Children = append(parent.Children, n)
+	}
+
+	for _, root := range t.Roots {
+		root.walk(func(n *ClassNode) {
+			if n.Parent != nil {
+				n.Depth = n.Parent.Depth + 1
+			}
+		})
+	}
+
+	return t
+}
+
+func (n *ClassNode) hasAncestor(a *ClassNode) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+// 自身と子孫を深さ優先でたどる。
+func (n *ClassNode) walk(f func(*ClassNode)) {
+	f(n)
+	for _, c := range n.Children {
+		c.walk(f)
+	}
+}
+
+// code のノードを返します。
+func (t *ClassTree) Node(code string) (*ClassNode, bool) {
+	n, ok := t.nodes[code]
+	return n, ok
+}
+
+// code の祖先をルートから順に返します。
+func (t *ClassTree) Ancestors(code string) []ClassObjClass {
+	n, ok := t.nodes[code]
+	if !ok {
+		return nil
+	}
+
+	var ancestors []ClassObjClass
+	for p := n.Parent; p != nil; p = p.Parent {
+		ancestors = append(ancestors, p.ClassObjClass)
+	}
+	for i, j := 0, len(ancestors)-1; i < j; i, j = i+1, j-1 {
+		ancestors[i], ancestors[j] = ancestors[j], ancestors[i]
+	}
+	return ancestors
+}
+
+// code の子孫を深さ優先で返します。
+func (t *ClassTree) Descendants(code string) []ClassObjClass {
+	n, ok := t.nodes[code]
+	if !ok {
+		return nil
+	}
+
+	var descendants []ClassObjClass
+	for _, c := range n.Children {
+		c.walk(func(d *ClassNode) {
+			descendants = append(descendants, d.ClassObjClass)
+		})
+	}
+	return descendants
+}
+
+// 子を持たない分類を返します。
+func (t *ClassTree) Leaves() []ClassObjClass {
+	var leaves []ClassObjClass
+	t.Walk(func(n *ClassNode) {
+		if len(n.Children) == 0 {
+			leaves = append(leaves, n.ClassObjClass)
+		}
+	})
+	return leaves
+}
+
+// 階層レベルが level の分類を返します。
+func (t *ClassTree) AtLevel(level int) []ClassObjClass {
+	var classes []ClassObjClass
+	t.Walk(func(n *ClassNode) {
+		if n.LevelNumber() == level {
+			classes = append(classes, n.ClassObjClass)
+		}
+	})
+	return classes
+}
+
+// ルートから code までの名前を ClassPathSeparator で連結して返します。
+//
+//	全国 > 東京都 > 千代田区
+func (t *ClassTree) Path(code string) string {
+	n, ok := t.nodes[code]
+	if !ok {
+		return ""
+	}
+
+	var names []string
+	for _, a := range t.Ancestors(code) {
+		names = append(names, a.Name)
+	}
+	names = append(names, n.Name)
+	return strings.Join(names, ClassPathSeparator)
+}
+
+// すべてのノードをルートから深さ優先でたどります。
+func (t *ClassTree) Walk(f func(*ClassNode)) {
+	for _, root := range t.Roots {
+		root.walk(f)
+	}
+}
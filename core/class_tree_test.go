@@ -0,0 +1,85 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func classCodes(classes []core.ClassObjClass) []string {
+	codes := make([]string, len(classes))
+	for i, c := range classes {
+		codes[i] = c.Code
+	}
+	return codes
+}
+
+func TestClassTree(t *testing.T) {
+	obj := core.ClassObj{
+		ID: "area",
+		Class: []core.ClassObjClass{
+			{Code: "00000", Name: "全国", Level: "1"},
+			{Code: "13000", Name: "東京都", Level: "2", ParentCode: "00000"},
+			{Code: "13101", Name: "千代田区", Level: "3", ParentCode: "13000"},
+			{Code: "13102", Name: "中央区", Level: "3", ParentCode: "13000"},
+			{Code: "27000", Name: "大阪府", Level: "2", ParentCode: "00000"},
+			{Code: "99000", Name: "その他", Level: "2", ParentCode: "98000"},
+			{Code: "A", Name: "分類なし"},
+		},
+	}
+	tree := obj.Tree()
+
+	var roots []string
+	for _, n := range tree.Roots {
+		roots = append(roots, n.Code)
+	}
+	if want := []string{"00000", "99000", "A"}; !reflect.DeepEqual(roots, want) {
+		t.Errorf("Roots = %v, want %v", roots, want)
+	}
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{name: "Ancestors", got: classCodes(tree.Ancestors("13101")), want: []string{"00000", "13000"}},
+		{name: "AncestorsOfRoot", got: classCodes(tree.Ancestors("00000")), want: []string{}},
+		{name: "Descendants", got: classCodes(tree.Descendants("00000")), want: []string{"13000", "13101", "13102", "27000"}},
+		{name: "Leaves", got: classCodes(tree.Leaves()), want: []string{"13101", "13102", "27000", "99000", "A"}},
+		{name: "AtLevel2", got: classCodes(tree.AtLevel(2)), want: []string{"13000", "27000", "99000"}},
+		{name: "AtLevel1", got: classCodes(tree.AtLevel(1)), want: []string{"00000", "A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+
+	if got := tree.Path("13101"); got != "全国 > 東京都 > 千代田区" {
+		t.Errorf("Path() = %s", got)
+	}
+	if n, ok := tree.Node("13102"); !ok || n.Depth != 2 || n.Parent.Code != "13000" {
+		t.Errorf("Node() = %+v, %v", n, ok)
+	}
+}
+
+func TestClassTreeCycle(t *testing.T) {
+	obj := core.ClassObj{
+		Class: []core.ClassObjClass{
+			{Code: "1", ParentCode: "2"},
+			{Code: "2", ParentCode: "1"},
+		},
+	}
+	tree := obj.Tree()
+
+	if len(tree.Roots) != 1 || tree.Roots[0].Code != "2" {
+		t.Errorf("Roots = %v", tree.Roots)
+	}
+	if got := classCodes(tree.Descendants("2")); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("Descendants() = %v", got)
+	}
+}
@@ -6,9 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	querystring "github.com/google/go-querystring/query"
 )
@@ -18,8 +19,15 @@ const (
 )
 
 type HttpClient struct {
-	httpClient http.Client
-	debug      bool
+	httpClient  http.Client
+	baseURL     string
+	https       bool
+	userAgent   string
+	timeout     *time.Duration
+	logger      *slog.Logger
+	logLevel    slog.Level
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
 }
 
 type IHttpClient interface {
@@ -28,85 +36,245 @@ type IHttpClient interface {
 	PostJsonWithQuery(ctx context.Context, path string, query any, structuredData any) (int, []byte, error)
 }
 
-func NewClient(debug bool) IHttpClient {
-	return &HttpClient{
+// レスポンスボディをメモリに読み込まずに返す IHttpClient
+//
+// 返された io.ReadCloser は呼び出し側で閉じる必要があります。
+type IHttpStreamClient interface {
+	GetStream(ctx context.Context, path string, query any) (int, io.ReadCloser, error)
+	PostJsonWithQueryStream(ctx context.Context, path string, query any, structuredData any) (int, io.ReadCloser, error)
+}
+
+// リクエストのログの出力先
+//
+// *log.Logger が実装しています。構造化されたログには WithSlogLogger を使って下さい。
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+type Option func(*HttpClient)
+
+// 使用する http.Client を指定します。nil の場合は無視します。
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *HttpClient) {
+		if httpClient != nil {
+			c.httpClient = *httpClient
+		}
+	}
+}
+
+// API のベース URL を指定します。
+//
+// キャッシュ用のプロキシやテスト用のサーバを使う場合に指定します。
+func WithBaseURL(baseURL string) Option {
+	return func(c *HttpClient) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// ベース URL が http:// の場合に https:// でリクエストします。
+func WithHTTPS() Option {
+	return func(c *HttpClient) {
+		c.https = true
+	}
+}
+
+// User-Agent ヘッダを指定します。
+func WithUserAgent(userAgent string) Option {
+	return func(c *HttpClient) {
+		c.userAgent = userAgent
+	}
+}
+
+// リクエストのタイムアウトを指定します。
+//
+// WithHTTPClient と併用した場合も、指定した http.Client のタイムアウトより優先されます。
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *HttpClient) {
+		c.timeout = &timeout
+	}
+}
+
+// リクエストのログの出力先を指定し、ログの出力を有効にします。
+//
+// ログは slog のテキスト形式で 1 行ずつ出力されます。
+func WithLogger(logger Logger) Option {
+	return func(c *HttpClient) {
+		c.logger = printfLogger(logger)
+	}
+}
+
+// opts を適用した IHttpClient を返します。
+func New(opts ...Option) IHttpClient {
+	c := &HttpClient{
 		httpClient: *http.DefaultClient,
-		debug:      debug,
+		baseURL:    ApiBaseURL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.timeout != nil {
+		c.httpClient.Timeout = *c.timeout
+	}
+	return c
+}
+
+// debug が true の場合、slog.Default() にリクエストのログを出力します。
+//
+// WithSlogLogger、WithLogger を指定した場合はそちらに出力します。新しいコードでは New を使って下さい。
+func NewClient(debug bool, opts ...Option) IHttpClient {
+	if debug {
+		opts = append([]Option{WithSlogLogger(slog.Default())}, opts...)
 	}
+	return New(opts...)
 }
 
-func (c *HttpClient) doRequest(req *http.Request) (int, []byte, error) {
-	if c.debug {
-		log.Printf("%s %s", req.Method, req.URL)
+// idempotent が false のリクエストは RetryPolicy.RetryNonIdempotent が true の場合のみリトライする。
+//
+// ログはレスポンスボディを閉じたときに出力する。
+func (c *HttpClient) doRequestStream(req *http.Request, endpoint string, idempotent bool) (int, io.ReadCloser, error) {
+	start := time.Now()
+	resp, retries, err := c.doWithRetry(req, endpoint, idempotent)
+	if err != nil {
+		err = redactURLError(err)
+		c.logRequest(req, endpoint, start, retries, 0, 0, nil, err)
+		return 0, nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	if c.logger == nil {
+		return resp.StatusCode, resp.Body, nil
+	}
+	return resp.StatusCode, &loggingBody{
+		ReadCloser: resp.Body,
+		done: func(n int64, head []byte) {
+			c.logRequest(req, endpoint, start, retries, resp.StatusCode, n, head, nil)
+		},
+	}, nil
+}
+
+func (c *HttpClient) doRequest(req *http.Request, endpoint string, idempotent bool) (int, []byte, error) {
+	statusCode, body, err := c.doRequestStream(req, endpoint, idempotent)
 	if err != nil {
 		return 0, nil, err
 	}
+	defer body.Close()
 
-	b, err := io.ReadAll(resp.Body)
+	b, err := io.ReadAll(body)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	return resp.StatusCode, b, nil
+	return statusCode, b, nil
 }
 
-func (c *HttpClient) request(ctx context.Context, method string, path string, structuredData any) (int, []byte, error) {
-	targetURL := urlWithXmlFormatFromPath(path)
+func (c *HttpClient) newRequest(ctx context.Context, method string, path string, structuredData any) (*http.Request, error) {
+	targetURL := c.urlFromPath(path)
 
 	data, err := querystring.Values(structuredData)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
 	var req *http.Request
 	if method == http.MethodGet {
 		req, err = http.NewRequestWithContext(ctx, method, targetURL, nil)
+		if err != nil {
+			return nil, err
+		}
 		req.URL.RawQuery = data.Encode()
 	} else if method == http.MethodPost {
 		req, err = http.NewRequestWithContext(ctx, method, targetURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
 		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		return nil, fmt.Errorf("unsupported method: %s", method)
+	}
+	c.setHeader(req)
+
+	return req, nil
+}
+
+func (c *HttpClient) newJsonRequestWithQuery(ctx context.Context, path string, query any, structuredData any) (*http.Request, error) {
+	targetURL := c.urlFromPath(path)
+
+	queryData, err := querystring.Values(query)
+	if err != nil {
+		return nil, err
 	}
+
+	data, err := json.Marshal(structuredData)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Add("Content-Type", "application/json")
+	req.URL.RawQuery = queryData.Encode()
+	c.setHeader(req)
 
-	return c.doRequest(req)
+	return req, nil
 }
 
 func (c *HttpClient) Get(ctx context.Context, path string, query any) (int, []byte, error) {
-	return c.request(ctx, http.MethodGet, path, query)
+	req, err := c.newRequest(ctx, http.MethodGet, path, query)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return c.doRequest(req, path, true)
 }
 
 func (c *HttpClient) Post(ctx context.Context, path string, data any) (int, []byte, error) {
-	return c.request(ctx, http.MethodPost, path, data)
+	req, err := c.newRequest(ctx, http.MethodPost, path, data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return c.doRequest(req, path, false)
 }
 
+// 統計データ一括取得のように、POST でもデータを変更しないリクエストに使うため冪等として扱う。
 func (c *HttpClient) PostJsonWithQuery(ctx context.Context, path string, query any, structuredData any) (int, []byte, error) {
-	targetURL := urlWithXmlFormatFromPath(path)
-
-	queryData, err := querystring.Values(query)
+	req, err := c.newJsonRequestWithQuery(ctx, path, query, structuredData)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	data, err := json.Marshal(structuredData)
+	return c.doRequest(req, path, true)
+}
+
+func (c *HttpClient) GetStream(ctx context.Context, path string, query any) (int, io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewBuffer(data))
-	req.Header.Add("Content-Type", "application/json")
-	req.URL.RawQuery = queryData.Encode()
+	return c.doRequestStream(req, path, true)
+}
+
+func (c *HttpClient) PostJsonWithQueryStream(ctx context.Context, path string, query any, structuredData any) (int, io.ReadCloser, error) {
+	req, err := c.newJsonRequestWithQuery(ctx, path, query, structuredData)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	return c.doRequest(req)
+	return c.doRequestStream(req, path, true)
 }
 
-func urlWithXmlFormatFromPath(path string) string {
-	return fmt.Sprintf("%s%s", ApiBaseURL, path)
+func (c *HttpClient) urlFromPath(path string) string {
+	baseURL := c.baseURL
+	if c.https && strings.HasPrefix(baseURL, "http://") {
+		baseURL = "https://" + strings.TrimPrefix(baseURL, "http://")
+	}
+	return fmt.Sprintf("%s%s", baseURL, path)
+}
+
+func (c *HttpClient) setHeader(req *http.Request) {
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 }
@@ -0,0 +1,157 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+type bufferLogger struct {
+	strings.Builder
+}
+
+func (l *bufferLogger) Printf(format string, v ...any) {
+	fmt.Fprintf(&l.Builder, format, v...)
+}
+
+func TestClientOptions(t *testing.T) {
+	var got *http.Request
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write(responseGetStatsList)
+	})
+
+	tests := []struct {
+		name          string
+		tls           bool
+		opts          func(srv *httptest.Server) []core.Option
+		wantPath      string
+		wantUserAgent string
+	}{
+		{
+			name: "BaseURL",
+			opts: func(srv *httptest.Server) []core.Option {
+				return []core.Option{core.WithBaseURL(srv.URL + "/rest/3.0/app/")}
+			},
+			wantPath:      "/rest/3.0/app/getStatsList",
+			wantUserAgent: "Go-http-client/1.1",
+		},
+		{
+			name: "UserAgent",
+			opts: func(srv *httptest.Server) []core.Option {
+				return []core.Option{core.WithBaseURL(srv.URL), core.WithUserAgent("e-stat-go-test")}
+			},
+			wantPath:      "/getStatsList",
+			wantUserAgent: "e-stat-go-test",
+		},
+		{
+			name: "HTTPS",
+			tls:  true,
+			opts: func(srv *httptest.Server) []core.Option {
+				return []core.Option{
+					core.WithBaseURL(strings.Replace(srv.URL, "https://", "http://", 1)),
+					core.WithHTTPS(),
+					core.WithHTTPClient(srv.Client()),
+				}
+			},
+			wantPath:      "/getStatsList",
+			wantUserAgent: "Go-http-client/1.1",
+		},
+		{
+			name: "Nil HTTPClient",
+			opts: func(srv *httptest.Server) []core.Option {
+				return []core.Option{core.WithBaseURL(srv.URL), core.WithHTTPClient(nil)}
+			},
+			wantPath:      "/getStatsList",
+			wantUserAgent: "Go-http-client/1.1",
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var srv *httptest.Server
+			if tt.tls {
+				srv = httptest.NewTLSServer(handler)
+			} else {
+				srv = httptest.NewServer(handler)
+			}
+			defer srv.Close()
+
+			ac := core.NewApiClient(core.New(tt.opts(srv)...), core.CommonParams{AppID: "test"})
+			if _, err := ac.GetStatsList(ctx, core.ParamsGetStatsList{}); err != nil {
+				t.Fatalf("GetStatsList() error = %v", err)
+			}
+
+			if got.URL.Path != tt.wantPath {
+				t.Errorf("path = %s, want %s", got.URL.Path, tt.wantPath)
+			}
+			if got.URL.Query().Get("appId") != "test" {
+				t.Errorf("query = %s", got.URL.RawQuery)
+			}
+			if ua := got.Header.Get("User-Agent"); ua != tt.wantUserAgent {
+				t.Errorf("User-Agent = %s, want %s", ua, tt.wantUserAgent)
+			}
+		})
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write(responseGetStatsList)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts []core.Option
+	}{
+		{
+			name: "Timeout",
+			opts: []core.Option{core.WithBaseURL(srv.URL), core.WithTimeout(10 * time.Millisecond)},
+		},
+		{
+			name: "Before HTTPClient",
+			opts: []core.Option{core.WithBaseURL(srv.URL), core.WithTimeout(10 * time.Millisecond), core.WithHTTPClient(&http.Client{})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := core.NewApiClient(core.New(tt.opts...), core.CommonParams{})
+
+			_, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{})
+			var netErr interface{ Timeout() bool }
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				t.Errorf("GetStatsList() error = %v, want timeout", err)
+			}
+		})
+	}
+}
+
+func TestClientLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(responseGetStatsList)
+	}))
+	defer srv.Close()
+
+	var logger bufferLogger
+	hc := core.NewClient(true, core.WithBaseURL(srv.URL), core.WithLogger(&logger))
+	ac := core.NewApiClient(hc, core.CommonParams{})
+
+	if _, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{}); err != nil {
+		t.Fatalf("GetStatsList() error = %v", err)
+	}
+	if logger.Len() == 0 {
+		t.Errorf("logger was not used")
+	}
+}
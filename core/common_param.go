@@ -16,7 +16,7 @@ type CommonParams struct {
 	// ・J：日本語 (省略値)
 	//
 	// ・E：英語
-	Lang string `url:"lang,omitempty" json:"lang,omitempty" xml:"LANG,omitempty"`
+	Lang Lang `url:"lang,omitempty" json:"lang,omitempty" xml:"LANG,omitempty"`
 }
 
 type TabulatCondition struct {
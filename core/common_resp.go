@@ -9,9 +9,10 @@ type ResponseResult struct {
 }
 
 type ResultInf struct {
-	FromNumber int `xml:"FROM_NUMBER"`
-	ToNumber   int `xml:"TO_NUMBER"`
-	NextKey    int `xml:"NEXT_KEY,omitempty"`
+	TotalNumber int `xml:"TOTAL_NUMBER,omitempty"`
+	FromNumber  int `xml:"FROM_NUMBER"`
+	ToNumber    int `xml:"TO_NUMBER"`
+	NextKey     int `xml:"NEXT_KEY,omitempty"`
 }
 
 type StatName struct {
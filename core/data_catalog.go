@@ -1,24 +1,32 @@
 package core
 
-import (
-	"context"
-	"encoding/xml"
-)
+import "context"
 
 type ParamsGetDataCatalog struct {
-	SurveyYears       string `url:"surveyYears,omitempty" xml:"SURVEY_YEARS,omitempty"`
-	OpenYears         string `url:"openYears,omitempty" xml:"OPEN_YEARS,omitempty"`
-	StatsField        int    `url:"statsField,omitempty" xml:"STATS_FIELD,omitempty"`
-	StatsCode         int    `url:"statsCode,omitempty" xml:"STATS_CODE,omitempty"`
-	SearchWord        string `url:"searchWord,omitempty" xml:"SEARCH_WORD,omitempty"`
-	CollectArea       int    `url:"collectArea,omitempty" xml:"COLLECT_AREA,omitempty"`
-	ExplanationGetFlg string `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG,omitempty"`
-	DataType          string `url:"dataType,omitempty" xml:"DATA_TYPE"`
-	StartPosition     int    `url:"startPosition,omitempty" xml:"START_POSITION,omitempty"`
-	CatalogId         int    `url:"catalogId,omitempty" xml:"CATALOG_ID"`
-	ResourceId        int    `url:"resourceId,omitempty" xml:"RESOURCE_ID"`
-	Limit             int    `url:"limit,omitempty" xml:"LIMIT,omitempty"`
-	UpdatedDate       string `url:"updatedDate,omitempty" xml:"UPDATED_DATE,omitempty"`
+	SurveyYears       string      `url:"surveyYears,omitempty" xml:"SURVEY_YEARS,omitempty"`
+	OpenYears         string      `url:"openYears,omitempty" xml:"OPEN_YEARS,omitempty"`
+	StatsField        StatsField  `url:"statsField,omitempty" xml:"STATS_FIELD,omitempty"`
+	StatsCode         int         `url:"statsCode,omitempty" xml:"STATS_CODE,omitempty"`
+	SearchWord        string      `url:"searchWord,omitempty" xml:"SEARCH_WORD,omitempty"`
+	CollectArea       CollectArea `url:"collectArea,omitempty" xml:"COLLECT_AREA,omitempty"`
+	ExplanationGetFlg Flag        `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG,omitempty"`
+	DataType          string      `url:"dataType,omitempty" xml:"DATA_TYPE"`
+	StartPosition     int         `url:"startPosition,omitempty" xml:"START_POSITION,omitempty"`
+	CatalogId         int         `url:"catalogId,omitempty" xml:"CATALOG_ID"`
+	ResourceId        int         `url:"resourceId,omitempty" xml:"RESOURCE_ID"`
+	Limit             int         `url:"limit,omitempty" xml:"LIMIT,omitempty"`
+	UpdatedDate       string      `url:"updatedDate,omitempty" xml:"UPDATED_DATE,omitempty"`
+}
+
+// パラメータを検証します。
+func (p ParamsGetDataCatalog) Validate() error {
+	var v paramValidator
+	v.statsField(p.StatsField)
+	v.collectArea(p.CollectArea)
+	v.flag("explanationGetFlg", p.ExplanationGetFlg)
+	v.nonNegative("startPosition", p.StartPosition)
+	v.nonNegative("limit", p.Limit)
+	return v.err()
 }
 
 type ParamsGetDataCatalogRoot struct {
@@ -101,7 +109,10 @@ type ResponseGetDataCatalogRoot struct {
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_1
 func (c *ApiClient) GetDataCatalog(ctx context.Context, params ParamsGetDataCatalog) (*ResponseGetDataCatalogRoot, error) {
-	_, body, err := c.HttpClient.Get(ctx, "/getDataCatalog", ParamsGetDataCatalogRoot{
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.Get(ctx, c.path("/getDataCatalog"), ParamsGetDataCatalogRoot{
 		CommonParams:         c.CommonParams,
 		ParamsGetDataCatalog: params,
 	})
@@ -110,9 +121,9 @@ func (c *ApiClient) GetDataCatalog(ctx context.Context, params ParamsGetDataCata
 	}
 
 	var data *ResponseGetDataCatalogRoot
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/getDataCatalog", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/getDataCatalog", statusCode, data.Result)
 }
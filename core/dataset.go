@@ -1,17 +1,23 @@
 package core
 
-import (
-	"context"
-	"encoding/xml"
-)
+import "context"
 
 type ParamsPostDataset struct {
 	DataSetID   string `url:"dataSetId,omitempty" xml:"DATA_SET_ID"`
 	StatsDataId string `url:"statsDataId,omitempty" xml:"STATS_DATA_ID"`
 	NarrowingConditon
-	OpenSpecified string `url:"openSpecified,omitempty" xml:"OPEN_SPECIFIED"`
-	ProcessMode   string `url:"processMode,omitempty" xml:"PROCESS_MODE"`
-	DataSetName   string `url:"dataSetName,omitempty" xml:"DATASET_NAME"`
+	OpenSpecified OpenSpecified `url:"openSpecified,omitempty" xml:"OPEN_SPECIFIED"`
+	ProcessMode   ProcessMode   `url:"processMode,omitempty" xml:"PROCESS_MODE"`
+	DataSetName   string        `url:"dataSetName,omitempty" xml:"DATASET_NAME"`
+}
+
+// パラメータを検証します。
+func (p ParamsPostDataset) Validate() error {
+	var v paramValidator
+	v.check(p.OpenSpecified.Valid(), "openSpecified", p.OpenSpecified, `must be "0" or "1"`)
+	v.check(p.ProcessMode.Valid(), "processMode", p.ProcessMode, `must be "E" or "D"`)
+	v.check(p.ProcessMode != ProcessModeDelete || p.DataSetID != "", "dataSetId", p.DataSetID, "required to delete a dataset")
+	return v.err()
 }
 
 type ParamsPostDatasetRoot struct {
@@ -51,7 +57,10 @@ type ResponsePostDatasetRoot struct {
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_4
 func (c *ApiClient) PostDataset(ctx context.Context, params ParamsPostDataset) (*ResponsePostDatasetRoot, error) {
-	_, body, err := c.HttpClient.Post(ctx, "/postDataset", ParamsPostDatasetRoot{
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.Post(ctx, c.path("/postDataset"), ParamsPostDatasetRoot{
 		CommonParams:      c.CommonParams,
 		ParamsPostDataset: params,
 	})
@@ -60,16 +69,23 @@ func (c *ApiClient) PostDataset(ctx context.Context, params ParamsPostDataset) (
 	}
 
 	var data *ResponsePostDatasetRoot
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/postDataset", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/postDataset", statusCode, data.Result)
 }
 
 type ParamsRefDataset struct {
 	DataSetID         string `url:"dataSetId,omitempty" xml:"DATA_SET_ID"`
-	ExplanationGetFlg string `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
+	ExplanationGetFlg Flag   `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
+}
+
+// パラメータを検証します。
+func (p ParamsRefDataset) Validate() error {
+	var v paramValidator
+	v.flag("explanationGetFlg", p.ExplanationGetFlg)
+	return v.err()
 }
 
 type ParamsRefDatasetRoot struct {
@@ -113,7 +129,10 @@ type ResponseRefDatasetRoot struct {
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_5
 func (c *ApiClient) RefDataset(ctx context.Context, params ParamsRefDataset) (*ResponseRefDatasetRoot, error) {
-	_, body, err := c.HttpClient.Get(ctx, "/refDataset", ParamsRefDatasetRoot{
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.Get(ctx, c.path("/refDataset"), ParamsRefDatasetRoot{
 		CommonParams:     c.CommonParams,
 		ParamsRefDataset: params,
 	})
@@ -122,16 +141,24 @@ func (c *ApiClient) RefDataset(ctx context.Context, params ParamsRefDataset) (*R
 	}
 
 	var data *ResponseRefDatasetRoot
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/refDataset", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/refDataset", statusCode, data.Result)
 }
 
 type ParamsGetDatasetList struct {
-	CollectArea       string `url:"collectArea,omitempty" xml:"COLLECT_AREA"`
-	ExplanationGetFlg string `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
+	CollectArea       CollectArea `url:"collectArea,omitempty" xml:"COLLECT_AREA"`
+	ExplanationGetFlg Flag        `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
+}
+
+// パラメータを検証します。
+func (p ParamsGetDatasetList) Validate() error {
+	var v paramValidator
+	v.collectArea(p.CollectArea)
+	v.flag("explanationGetFlg", p.ExplanationGetFlg)
+	return v.err()
 }
 
 type ParamsGetDatasetListRoot struct {
@@ -161,7 +188,10 @@ type ResponseGetDatasetListRoot struct {
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_5
 func (c *ApiClient) GetDatasetList(ctx context.Context, params ParamsGetDatasetList) (*ResponseGetDatasetListRoot, error) {
-	_, body, err := c.HttpClient.Get(ctx, "/refDataset", ParamsGetDatasetListRoot{
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.Get(ctx, c.path("/refDataset"), ParamsGetDatasetListRoot{
 		CommonParams:         c.CommonParams,
 		ParamsGetDatasetList: params,
 	})
@@ -170,9 +200,9 @@ func (c *ApiClient) GetDatasetList(ctx context.Context, params ParamsGetDatasetL
 	}
 
 	var data *ResponseGetDatasetListRoot
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/refDataset", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/refDataset", statusCode, data.Result)
 }
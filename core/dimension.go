@@ -0,0 +1,207 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DataInfValue の属性として出力される分類事項の ID (出力順)
+var dimensionIDs = []string{
+	"tab",
+	"cat01", "cat02", "cat03", "cat04", "cat05",
+	"cat06", "cat07", "cat08", "cat09", "cat10",
+	"cat11", "cat12", "cat13", "cat14", "cat15",
+	"area", "time",
+}
+
+// 分類事項の ID ("tab"、"cat01"〜"cat15"、"area"、"time") を DataInfValue の属性の出力順に返します。
+func DimensionIDs() []string {
+	return append([]string{}, dimensionIDs...)
+}
+
+// id ("tab"、"cat01"〜"cat15"、"area"、"time") の分類事項のコードを返します。
+func (v DataInfValue) Dim(id string) string {
+	switch id {
+	case "tab":
+		return v.Tab
+	case "cat01":
+		return v.Cat01
+	case "cat02":
+		return v.Cat02
+	case "cat03":
+		return v.Cat03
+	case "cat04":
+		return v.Cat04
+	case "cat05":
+		return v.Cat05
+	case "cat06":
+		return v.Cat06
+	case "cat07":
+		return v.Cat07
+	case "cat08":
+		return v.Cat08
+	case "cat09":
+		return v.Cat09
+	case "cat10":
+		return v.Cat10
+	case "cat11":
+		return v.Cat11
+	case "cat12":
+		return v.Cat12
+	case "cat13":
+		return v.Cat13
+	case "cat14":
+		return v.Cat14
+	case "cat15":
+		return v.Cat15
+	case "area":
+		return v.Area
+	case "time":
+		return v.Time
+	}
+	return ""
+}
+
+// 分類事項 n (1〜15) のコードを返します。
+func (v DataInfValue) Cat(n int) string {
+	return v.Dim(categoryID(n))
+}
+
+// 値を持つ分類事項の ID とコードを返します。
+func (v DataInfValue) Dimensions() map[string]string {
+	dims := map[string]string{}
+	for _, id := range dimensionIDs {
+		if code := v.Dim(id); code != "" {
+			dims[id] = code
+		}
+	}
+	return dims
+}
+
+// 分類事項 1 つ分の絞り込み条件
+type DimCondition struct {
+	Level    string
+	Code     string
+	CodeFrom string
+	CodeTo   string
+}
+
+// 分類事項の絞り込み条件のフィールド
+type conditionFields struct {
+	level, code, from, to *string
+}
+
+func (c *CategoryCondition) fields(n int) (conditionFields, bool) {
+	switch n {
+	case 1:
+		return conditionFields{&c.LevelCat01, &c.CodeCat01, &c.CodeCat01From, &c.CodeCat01To}, true
+	case 2:
+		return conditionFields{&c.LevelCat02, &c.CodeCat02, &c.CodeCat02From, &c.CodeCat02To}, true
+	case 3:
+		return conditionFields{&c.LevelCat03, &c.CodeCat03, &c.CodeCat03From, &c.CodeCat03To}, true
+	case 4:
+		return conditionFields{&c.LevelCat04, &c.CodeCat04, &c.CodeCat04From, &c.CodeCat04To}, true
+	case 5:
+		return conditionFields{&c.LevelCat05, &c.CodeCat05, &c.CodeCat05From, &c.CodeCat05To}, true
+	case 6:
+		return conditionFields{&c.LevelCat06, &c.CodeCat06, &c.CodeCat06From, &c.CodeCat06To}, true
+	case 7:
+		return conditionFields{&c.LevelCat07, &c.CodeCat07, &c.CodeCat07From, &c.CodeCat07To}, true
+	case 8:
+		return conditionFields{&c.LevelCat08, &c.CodeCat08, &c.CodeCat08From, &c.CodeCat08To}, true
+	case 9:
+		return conditionFields{&c.LevelCat09, &c.CodeCat09, &c.CodeCat09From, &c.CodeCat09To}, true
+	case 10:
+		return conditionFields{&c.LevelCat10, &c.CodeCat10, &c.CodeCat10From, &c.CodeCat10To}, true
+	case 11:
+		return conditionFields{&c.LevelCat11, &c.CodeCat11, &c.CodeCat11From, &c.CodeCat11To}, true
+	case 12:
+		return conditionFields{&c.LevelCat12, &c.CodeCat12, &c.CodeCat12From, &c.CodeCat12To}, true
+	case 13:
+		return conditionFields{&c.LevelCat13, &c.CodeCat13, &c.CodeCat13From, &c.CodeCat13To}, true
+	case 14:
+		return conditionFields{&c.LevelCat14, &c.CodeCat14, &c.CodeCat14From, &c.CodeCat14To}, true
+	case 15:
+		return conditionFields{&c.LevelCat15, &c.CodeCat15, &c.CodeCat15From, &c.CodeCat15To}, true
+	}
+	return conditionFields{}, false
+}
+
+// "cat01" のような分類事項の ID から番号を返す。
+func categoryNumber(id string) (int, bool) {
+	if !strings.HasPrefix(id, "cat") || len(id) != 5 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(id[3:])
+	if err != nil || n < 1 || n > 15 {
+		return 0, false
+	}
+	return n, true
+}
+
+func categoryID(n int) string {
+	return fmt.Sprintf("cat%02d", n)
+}
+
+// id の分類事項の絞り込み条件のフィールドを返す。
+func (c *NarrowingConditon) fields(id string) (conditionFields, bool) {
+	switch id {
+	case "tab":
+		return conditionFields{&c.LevelTab, &c.CodeTab, &c.CodeTabFrom, &c.CodeTabTo}, true
+	case "time":
+		return conditionFields{&c.LevelTime, &c.CodeTime, &c.CodeTimeFrom, &c.CodeTimeTo}, true
+	case "area":
+		return conditionFields{&c.LevelArea, &c.CodeArea, &c.CodeAreaFrom, &c.CodeAreaTo}, true
+	}
+	if n, ok := categoryNumber(id); ok {
+		return c.CategoryCondition.fields(n)
+	}
+	return conditionFields{}, false
+}
+
+func (f conditionFields) get() DimCondition {
+	return DimCondition{Level: *f.level, Code: *f.code, CodeFrom: *f.from, CodeTo: *f.to}
+}
+
+func (f conditionFields) set(cond DimCondition) {
+	*f.level, *f.code, *f.from, *f.to = cond.Level, cond.Code, cond.CodeFrom, cond.CodeTo
+}
+
+// 分類事項 n (1〜15) の絞り込み条件を返します。n が範囲外の場合はエラーを返します。
+func (c CategoryCondition) Get(n int) (DimCondition, error) {
+	f, ok := c.fields(n)
+	if !ok {
+		return DimCondition{}, fmt.Errorf("e-stat: category %d out of range", n)
+	}
+	return f.get(), nil
+}
+
+// 分類事項 n (1〜15) の絞り込み条件を設定します。n が範囲外の場合はエラーを返します。
+func (c *CategoryCondition) Set(n int, cond DimCondition) error {
+	f, ok := c.fields(n)
+	if !ok {
+		return fmt.Errorf("e-stat: category %d out of range", n)
+	}
+	f.set(cond)
+	return nil
+}
+
+// id ("tab"、"cat01"〜"cat15"、"area"、"time") の絞り込み条件を返します。
+func (c NarrowingConditon) GetDim(id string) (DimCondition, bool) {
+	f, ok := c.fields(id)
+	if !ok {
+		return DimCondition{}, false
+	}
+	return f.get(), true
+}
+
+// id ("tab"、"cat01"〜"cat15"、"area"、"time") の絞り込み条件を設定します。
+func (c *NarrowingConditon) SetDim(id string, cond DimCondition) error {
+	f, ok := c.fields(id)
+	if !ok {
+		return fmt.Errorf("e-stat: unknown dimension %q", id)
+	}
+	f.set(cond)
+	return nil
+}
@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-querystring/query"
+	"github.com/itok01/e-stat-go/core"
+)
+
+func TestCategoryConditionSetGet(t *testing.T) {
+	var c core.CategoryCondition
+	want := core.DimCondition{Level: "1-2", Code: "001,002", CodeFrom: "001", CodeTo: "009"}
+	if err := c.Set(5, want); err != nil {
+		t.Fatalf("Set(5) error = %v", err)
+	}
+
+	if got, err := c.Get(5); err != nil || got != want {
+		t.Errorf("Get(5) = %+v, %v, want %+v", got, err, want)
+	}
+	if c.LevelCat05 != "1-2" || c.CodeCat05 != "001,002" || c.CodeCat05From != "001" || c.CodeCat05To != "009" {
+		t.Errorf("fields = %+v", c)
+	}
+	if got, err := c.Get(4); err != nil || got != (core.DimCondition{}) {
+		t.Errorf("Get(4) = %+v, %v, want zero", got, err)
+	}
+
+	values, err := query.Values(core.NarrowingConditon{CategoryCondition: c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"lvCat05": "1-2", "cdCat05": "001,002", "cdCat05From": "001", "cdCat05To": "009"} {
+		if got := values.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestCategoryConditionOutOfRange(t *testing.T) {
+	for _, n := range []int{0, 16} {
+		var c core.CategoryCondition
+		if _, err := c.Get(n); err == nil {
+			t.Errorf("Get(%d) error = nil", n)
+		}
+		if err := c.Set(n, core.DimCondition{Code: "1"}); err == nil {
+			t.Errorf("Set(%d) error = nil", n)
+		}
+		if c != (core.CategoryCondition{}) {
+			t.Errorf("Set(%d) modified the condition: %+v", n, c)
+		}
+	}
+}
+
+func TestNarrowingConditionDim(t *testing.T) {
+	var c core.NarrowingConditon
+	for _, id := range []string{"tab", "cat15", "area", "time"} {
+		if err := c.SetDim(id, core.DimCondition{Code: id}); err != nil {
+			t.Fatalf("SetDim(%q) error = %v", id, err)
+		}
+		if got, ok := c.GetDim(id); !ok || got.Code != id {
+			t.Errorf("GetDim(%q) = %+v, %v", id, got, ok)
+		}
+	}
+	if c.CodeTab != "tab" || c.CodeCat15 != "cat15" || c.CodeArea != "area" || c.CodeTime != "time" {
+		t.Errorf("fields = %+v", c)
+	}
+
+	if err := c.SetDim("cat16", core.DimCondition{}); err == nil {
+		t.Error("SetDim(cat16) error = nil")
+	}
+	if _, ok := c.GetDim("unit"); ok {
+		t.Error("GetDim(unit) ok = true")
+	}
+}
+
+func TestDataInfValueDim(t *testing.T) {
+	v := core.DataInfValue{Tab: "01", Cat01: "A", Cat05: "E", Cat15: "O", Area: "13000", Time: "2020000000", Unit: "人"}
+
+	for n, want := range map[int]string{1: "A", 2: "", 5: "E", 15: "O", 16: ""} {
+		if got := v.Cat(n); got != want {
+			t.Errorf("Cat(%d) = %q, want %q", n, got, want)
+		}
+	}
+	if got := v.Dim("cat05"); got != "E" {
+		t.Errorf(`Dim("cat05") = %q, want "E"`, got)
+	}
+	if got := v.Dim("unit"); got != "" {
+		t.Errorf(`Dim("unit") = %q, want ""`, got)
+	}
+
+	want := map[string]string{"tab": "01", "cat01": "A", "cat05": "E", "cat15": "O", "area": "13000", "time": "2020000000"}
+	if got := v.Dimensions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dimensions() = %v, want %v", got, want)
+	}
+}
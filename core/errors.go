@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RESULT の STATUS
+//
+// 0 は正常終了、1〜99 は警告 (データは返されます)、100 以上はエラーを表します。
+//
+// https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_4
+const (
+	// 正常に終了しました。
+	ResultStatusOK = 0
+
+	// 正常に終了しましたが、該当データはありませんでした。
+	ResultStatusNoData = 1
+
+	// 正常に終了しましたが、一部にエラーがあります。
+	ResultStatusPartialError = 2
+
+	// 認証に失敗しました。アプリケーションIDを確認して下さい。
+	ResultStatusInvalidAppID = 100
+
+	// これ以上の STATUS はエラーです。
+	resultStatusError = 100
+
+	// 100 番台はパラメータに関するエラー、それ以上はサーバ側のエラーです。
+	resultStatusServerError = 200
+)
+
+var (
+	// 該当データがない
+	ErrNoData = errors.New("e-stat: no data")
+
+	// 一部にエラーがある
+	ErrPartialError = errors.New("e-stat: partial error")
+
+	// アプリケーションIDが不正
+	ErrInvalidAppID = errors.New("e-stat: invalid application id")
+
+	// パラメータが不正
+	ErrInvalidParameter = errors.New("e-stat: invalid parameter")
+
+	// サーバ側のエラー
+	ErrServer = errors.New("e-stat: server error")
+
+	// HTTP のステータスコードが 2xx ではなく、レスポンスも読み込めない
+	ErrHTTPStatus = errors.New("e-stat: unexpected http status")
+)
+
+// 警告 (STATUS 1〜99) の扱い
+type WarningPolicy int
+
+const (
+	// 警告を APIError として返す (省略値)
+	WarningAsError WarningPolicy = iota
+
+	// 警告を無視する
+	WarningIgnore
+)
+
+// 警告の扱いを指定します。
+func WithWarningPolicy(policy WarningPolicy) ApiOption {
+	return func(c *ApiClient) {
+		c.WarningPolicy = policy
+	}
+}
+
+// API が返したエラー
+//
+// STATUS が 0 以外の場合、各メソッドはレスポンスとともに *APIError を返します。
+// errors.Is で ErrNoData などと比較できます。
+type APIError struct {
+	Endpoint   string
+	HTTPStatus int
+	Status     int
+	ErrorMsg   string
+	Date       time.Time
+
+	// レスポンスの読み込みに失敗した場合の原因
+	Err error
+}
+
+func (e *APIError) Error() string {
+	if e.Status == ResultStatusOK {
+		if e.Err != nil {
+			return fmt.Sprintf("e-stat: %s: http status %d: %v", e.Endpoint, e.HTTPStatus, e.Err)
+		}
+		return fmt.Sprintf("e-stat: %s: http status %d: %s", e.Endpoint, e.HTTPStatus, e.ErrorMsg)
+	}
+	return fmt.Sprintf("e-stat: %s: status %d: %s", e.Endpoint, e.Status, e.ErrorMsg)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNoData:
+		return e.Status == ResultStatusNoData
+	case ErrPartialError:
+		return e.Status == ResultStatusPartialError
+	case ErrInvalidAppID:
+		return e.Status == ResultStatusInvalidAppID
+	case ErrInvalidParameter:
+		return e.Status > ResultStatusInvalidAppID && e.Status < resultStatusServerError
+	case ErrServer:
+		return e.Status >= resultStatusServerError
+	case ErrHTTPStatus:
+		return e.Status == ResultStatusOK && !isSuccessHTTPStatus(e.HTTPStatus)
+	}
+	return false
+}
+
+// 警告 (STATUS 1〜99) かどうか
+func (e *APIError) IsWarning() bool {
+	return e.Status > ResultStatusOK && e.Status < resultStatusError
+}
+
+// ステータスコードを返さない IHttpClient のため、0 は成功として扱う。
+func isSuccessHTTPStatus(statusCode int) bool {
+	return statusCode == 0 || statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+}
+
+// RESULT を確認し、正常終了でなければ APIError を返す。
+func (c *ApiClient) checkResult(endpoint string, statusCode int, result ResponseResult) error {
+	if result.Status == ResultStatusOK {
+		return decodeError(endpoint, statusCode, nil)
+	}
+	if result.Status < resultStatusError && c.WarningPolicy == WarningIgnore {
+		return nil
+	}
+
+	return &APIError{
+		Endpoint:   endpoint,
+		HTTPStatus: statusCode,
+		Status:     result.Status,
+		ErrorMsg:   result.ErrorMsg,
+		Date:       result.Date,
+	}
+}
+
+// レスポンスを読み込めなかった場合のエラーを返す。
+func decodeError(endpoint string, statusCode int, err error) error {
+	if isSuccessHTTPStatus(statusCode) {
+		return err
+	}
+
+	return &APIError{
+		Endpoint:   endpoint,
+		HTTPStatus: statusCode,
+		ErrorMsg:   http.StatusText(statusCode),
+		Err:        err,
+	}
+}
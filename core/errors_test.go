@@ -0,0 +1,108 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+)
+
+// すべてのリクエストに同じレスポンスを返す IHttpClient を返す。
+func resultHttpClient(statusCode int, body []byte) estattest.ClientFunc {
+	return func(ctx context.Context, r estattest.ClientRequest) (int, []byte, error) {
+		return statusCode, body, nil
+	}
+}
+
+func statsListWithStatus(status int, msg string) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<GET_STATS_LIST>
+    <RESULT>
+        <STATUS>%d</STATUS>
+        <ERROR_MSG>%s</ERROR_MSG>
+        <DATE>2022-10-29T18:20:05.880+09:00</DATE>
+    </RESULT>
+</GET_STATS_LIST>`, status, msg))
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       []byte
+		opts       []core.ApiOption
+		want       error
+		wantStatus int
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			body:       statsListWithStatus(0, "正常に終了しました。"),
+		},
+		{
+			name:       "NoData",
+			statusCode: http.StatusOK,
+			body:       statsListWithStatus(1, "正常に終了しましたが、該当データはありませんでした。"),
+			want:       core.ErrNoData,
+			wantStatus: 1,
+		},
+		{
+			name:       "NoDataIgnored",
+			statusCode: http.StatusOK,
+			body:       statsListWithStatus(1, "正常に終了しましたが、該当データはありませんでした。"),
+			opts:       []core.ApiOption{core.WithWarningPolicy(core.WarningIgnore)},
+		},
+		{
+			name:       "InvalidAppID",
+			statusCode: http.StatusOK,
+			body:       statsListWithStatus(100, "認証に失敗しました。アプリケーションIDを確認して下さい。"),
+			opts:       []core.ApiOption{core.WithWarningPolicy(core.WarningIgnore)},
+			want:       core.ErrInvalidAppID,
+			wantStatus: 100,
+		},
+		{
+			name:       "InvalidParameter",
+			statusCode: http.StatusOK,
+			body:       statsListWithStatus(101, "パラメータが不正です。"),
+			want:       core.ErrInvalidParameter,
+			wantStatus: 101,
+		},
+		{
+			name:       "Maintenance",
+			statusCode: http.StatusServiceUnavailable,
+			body:       []byte("<html><body>メンテナンス中</body></html>"),
+			want:       core.ErrHTTPStatus,
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := core.NewApiClient(resultHttpClient(tt.statusCode, tt.body), core.CommonParams{}, tt.opts...)
+
+			_, err := ac.GetStatsList(ctx, core.ParamsGetStatsList{})
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("GetStatsList() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetStatsList() error = %v, want %v", err, tt.want)
+			}
+
+			var apiErr *core.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetStatsList() error = %T, want *core.APIError", err)
+			}
+			if apiErr.Status != tt.wantStatus || apiErr.HTTPStatus != tt.statusCode || apiErr.Endpoint != "/getStatsList" {
+				t.Errorf("GetStatsList() error = %+v", apiErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package estattest
+
+import (
+	"context"
+	"net/http"
+)
+
+// ClientFunc に渡すリクエスト
+type ClientRequest struct {
+	// http.MethodGet か http.MethodPost
+	Method string
+
+	Path string
+
+	// Get と PostJsonWithQuery のクエリ、Post のデータ
+	Query any
+
+	// PostJsonWithQuery の JSON のボディ。それ以外は nil
+	Body any
+}
+
+// 関数でレスポンスを返す IHttpClient
+//
+// Get、Post、PostJsonWithQuery のいずれも関数を呼びます。サーバを起動せずに、
+// 特定のレスポンスやリクエストの内容を確かめるテストに使えます。
+//
+//	hc := estattest.ClientFunc(func(ctx context.Context, r estattest.ClientRequest) (int, []byte, error) {
+//		return http.StatusServiceUnavailable, nil, nil
+//	})
+//	ac := core.NewApiClient(hc, core.CommonParams{})
+type ClientFunc func(ctx context.Context, r ClientRequest) (int, []byte, error)
+
+func (f ClientFunc) Get(ctx context.Context, path string, query any) (int, []byte, error) {
+	return f(ctx, ClientRequest{Method: http.MethodGet, Path: path, Query: query})
+}
+
+func (f ClientFunc) Post(ctx context.Context, path string, data any) (int, []byte, error) {
+	return f(ctx, ClientRequest{Method: http.MethodPost, Path: path, Query: data})
+}
+
+func (f ClientFunc) PostJsonWithQuery(ctx context.Context, path string, query any, structuredData any) (int, []byte, error) {
+	return f(ctx, ClientRequest{Method: http.MethodPost, Path: path, Query: query, Body: structuredData})
+}
@@ -0,0 +1,258 @@
+package estattest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// e-Stat の DATE の形式
+const dateLayout = "2006-01-02T15:04:05.000-07:00"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// 指定されたパラメータだけを出力する要素
+var sparseElements = map[string]bool{
+	"PARAMETER":      true,
+	"PARAMETER_LIST": true,
+}
+
+// レスポンスの要素
+type node struct {
+	name     string
+	attrs    []attr
+	text     string
+	number   bool
+	children []*node
+}
+
+type attr struct {
+	name   string
+	value  string
+	number bool
+}
+
+func (n *node) empty() bool {
+	return len(n.attrs) == 0 && n.text == "" && len(n.children) == 0
+}
+
+// v を xml タグに従って name の要素にする。
+//
+// e-Stat は値のない要素を出力しないため、空文字列と空の要素は省略する。
+// sparse が true の場合は 0 も省略する。
+// xml タグのないフィールド (CommonParams.AppID など) は出力しない。
+func encodeNode(name string, v reflect.Value, sparse bool) *node {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	n := &node{name: name}
+	if v.Kind() == reflect.Struct && v.Type() != timeType {
+		n.encodeFields(v, sparse)
+	} else if text, ok := encodeText(v, sparse); ok {
+		n.text, n.number = text, isNumber(v)
+	}
+	if n.empty() {
+		return nil
+	}
+	return n
+}
+
+func (n *node) encodeFields(v reflect.Value, sparse bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		tag, ok := f.Tag.Lookup("xml")
+		if tag == "-" || (!ok && !f.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			n.encodeFields(fv, sparse)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && fv.IsZero() {
+			continue
+		}
+
+		switch {
+		case strings.Contains(opts, "attr"):
+			if text, ok := encodeText(fv, sparse); ok {
+				n.attrs = append(n.attrs, attr{name: name, value: text, number: isNumber(fv)})
+			}
+		case strings.Contains(opts, "innerxml") || strings.Contains(opts, "chardata"):
+			if text, ok := encodeText(fv, sparse); ok {
+				n.text, n.number = text, isNumber(fv)
+			}
+		case fv.Kind() == reflect.Slice:
+			for j := 0; j < fv.Len(); j++ {
+				if child := encodeNode(name, fv.Index(j), sparse); child != nil {
+					n.children = append(n.children, child)
+				}
+			}
+		default:
+			if child := encodeNode(name, fv, sparse || sparseElements[name]); child != nil {
+				n.children = append(n.children, child)
+			}
+		}
+	}
+}
+
+// 値を要素のテキストにする。出力しない場合は false を返す。
+func encodeText(v reflect.Value, sparse bool) (string, bool) {
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return "", false
+		}
+		return t.Format(dateLayout), true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), v.String() != ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), !sparse || v.Int() != 0
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), !sparse || v.Float() != 0
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), !sparse || v.Bool()
+	}
+	return "", false
+}
+
+// JSON 形式で数値として出力するかを返す。
+//
+// e-Stat は整数のフィールドを数値で出力する。"0001" のようなコードは文字列のフィールドのため、文字列のまま出力する。
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// XML 形式で書き出す。
+func (n *node) writeXML(buf *bytes.Buffer) {
+	buf.WriteString("<" + n.name)
+	for _, a := range n.attrs {
+		buf.WriteString(" " + a.name + `="`)
+		xml.EscapeText(buf, []byte(a.value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+	xml.EscapeText(buf, []byte(n.text))
+	for _, child := range n.children {
+		child.writeXML(buf)
+	}
+	buf.WriteString("</" + n.name + ">")
+}
+
+// e-Stat の JSON 形式で書き出す。
+//
+// 属性は "@" を前置したキー、テキストは "$" をキーとし、属性も子要素もない要素はテキストのみを出力する。
+// 同じ名前の子要素が 1 つの場合は配列にしない。
+func (n *node) writeJSON(buf *bytes.Buffer) {
+	if len(n.attrs) == 0 && len(n.children) == 0 {
+		writeJSONText(buf, n.text, n.number)
+		return
+	}
+
+	buf.WriteString("{")
+	first := true
+	key := func(k string) {
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		writeJSONString(buf, k)
+		buf.WriteString(":")
+	}
+
+	for _, a := range n.attrs {
+		key("@" + a.name)
+		writeJSONText(buf, a.value, a.number)
+	}
+
+	var names []string
+	groups := map[string][]*node{}
+	for _, child := range n.children {
+		if _, ok := groups[child.name]; !ok {
+			names = append(names, child.name)
+		}
+		groups[child.name] = append(groups[child.name], child)
+	}
+	for _, name := range names {
+		key(name)
+		group := groups[name]
+		if len(group) == 1 {
+			group[0].writeJSON(buf)
+			continue
+		}
+		buf.WriteString("[")
+		for i, child := range group {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			child.writeJSON(buf)
+		}
+		buf.WriteString("]")
+	}
+
+	if n.text != "" {
+		key("$")
+		writeJSONText(buf, n.text, n.number)
+	}
+	buf.WriteString("}")
+}
+
+func writeJSONText(buf *bytes.Buffer, s string, number bool) {
+	if number {
+		buf.WriteString(s)
+		return
+	}
+	writeJSONString(buf, s)
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+
+	// Encode が付ける改行を取り除く
+	buf.Truncate(buf.Len() - 1)
+}
+
+// root を名前とする v のレスポンスを返す。
+func encodeResponse(root string, v any, jsonFormat bool) []byte {
+	n := encodeNode(root, reflect.ValueOf(v), false)
+	if n == nil {
+		n = &node{name: root}
+	}
+
+	var buf bytes.Buffer
+	if jsonFormat {
+		buf.WriteString("{")
+		writeJSONString(&buf, root)
+		buf.WriteString(":")
+		n.writeJSON(&buf)
+		buf.WriteString("}")
+		return buf.Bytes()
+	}
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	n.writeXML(&buf)
+	return buf.Bytes()
+}
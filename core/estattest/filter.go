@@ -0,0 +1,144 @@
+package estattest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+// 絞り込み条件
+type filter struct {
+	// 分類事項の ID ごとのコードの条件。すべてを満たす値を返す
+	dims map[string][]func(code string) bool
+}
+
+// class を元に conditions のすべてを満たす値を返す filter を作る。
+func newFilter(class core.ClassInf, conditions []core.NarrowingConditon) (*filter, error) {
+	objs := map[string]core.ClassObj{}
+	for _, obj := range class.ClassObj {
+		objs[obj.ID] = obj
+	}
+
+	f := &filter{dims: map[string][]func(string) bool{}}
+	for _, cond := range conditions {
+		for _, id := range core.DimensionIDs() {
+			c, _ := cond.GetDim(id)
+			if c == (core.DimCondition{}) {
+				continue
+			}
+			match, err := newCodeMatcher(objs[id], c)
+			if err != nil {
+				name := strings.ToUpper(id[:1]) + id[1:]
+				return nil, fmt.Errorf("パラメータ lv%s が不正です。", name)
+			}
+			f.dims[id] = append(f.dims[id], match)
+		}
+	}
+	return f, nil
+}
+
+// 階層レベル、コード、コードの範囲の条件を満たすかを返す関数を作る。
+//
+// コードの範囲はメタ情報の CLASS の順で比較し、メタ情報にないコードは文字列として比較する。
+func newCodeMatcher(obj core.ClassObj, cond core.DimCondition) (func(code string) bool, error) {
+	index := map[string]int{}
+	levels := map[string]int{}
+	for i, c := range obj.Class {
+		if _, ok := index[c.Code]; !ok {
+			index[c.Code] = i
+			levels[c.Code], _ = strconv.Atoi(c.Level)
+		}
+	}
+
+	var levelFrom, levelTo int
+	if cond.Level != "" {
+		var err error
+		if levelFrom, levelTo, err = core.ParseLevel(cond.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	var codes map[string]bool
+	if cond.Code != "" {
+		codes = map[string]bool{}
+		for _, code := range strings.Split(cond.Code, ",") {
+			codes[strings.TrimSpace(code)] = true
+		}
+	}
+
+	// a が b より前か
+	before := func(a string, b string) bool {
+		i, aok := index[a]
+		j, bok := index[b]
+		if aok && bok {
+			return i < j
+		}
+		return a < b
+	}
+
+	return func(code string) bool {
+		if codes != nil && !codes[code] {
+			return false
+		}
+		if cond.Level != "" {
+			level := levels[code]
+			if level == 0 || (levelFrom != 0 && level < levelFrom) || (levelTo != 0 && level > levelTo) {
+				return false
+			}
+		}
+		if cond.CodeFrom != "" && before(code, cond.CodeFrom) {
+			return false
+		}
+		if cond.CodeTo != "" && before(cond.CodeTo, code) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+func (f *filter) match(v core.DataInfValue) bool {
+	for id, matches := range f.dims {
+		code := v.Dim(id)
+		for _, match := range matches {
+			if !match(code) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (f *filter) count(values []core.DataInfValue) int {
+	n := 0
+	for _, v := range values {
+		if f.match(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// 絞り込み条件を満たす分類だけのメタ情報を返す。
+func (f *filter) class(class core.ClassInf) core.ClassInf {
+	objs := make([]core.ClassObj, len(class.ClassObj))
+	for i, obj := range class.ClassObj {
+		matches := f.dims[obj.ID]
+		if len(matches) > 0 {
+			var classes []core.ClassObjClass
+			for _, c := range obj.Class {
+				ok := true
+				for _, match := range matches {
+					ok = ok && match(c.Code)
+				}
+				if ok {
+					classes = append(classes, c)
+				}
+			}
+			obj.Class = classes
+		}
+		objs[i] = obj
+	}
+	return core.ClassInf{ClassObj: objs}
+}
@@ -0,0 +1,744 @@
+package estattest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+// limit を省略した場合の件数
+const defaultLimit = 100000
+
+// 集計地域区分 (collectArea) と TableInf.CollectArea の対応
+var collectAreaNames = map[core.CollectArea]string{
+	core.CollectAreaNational:     "全国",
+	core.CollectAreaPrefecture:   "都道府県",
+	core.CollectAreaMunicipality: "市区町村",
+}
+
+// url タグに従ってパラメータを v に読み込む。
+func decodeParams(params url.Values, v any) error {
+	return decodeParamFields(params, reflect.ValueOf(v).Elem())
+}
+
+func decodeParamFields(params url.Values, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		name, _, _ := strings.Cut(f.Tag.Get("url"), ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			if err := decodeParamFields(params, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+
+		s := params.Get(name)
+		if s == "" {
+			continue
+		}
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(s)
+		case reflect.Int:
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return fmt.Errorf("パラメータ %s が不正です。", name)
+			}
+			fv.SetInt(int64(n))
+		}
+	}
+	return nil
+}
+
+// 統計表情報取得
+func (s *Server) getStatsList(r *request) (string, any) {
+	var params core.ParamsGetStatsList
+	res := core.ResponseGetStatsList{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "GET_STATS_LIST", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res.Parameter = core.ResponseGetStatsListParameter{CommonParams: commonParams(r.params), ParamsGetStatsList: params}
+
+	var tables []core.TableInf
+	for _, id := range s.tableIDs {
+		inf := s.tables[id].Inf
+		if matchStatsList(inf, params) {
+			tables = append(tables, explain(inf, params.ExplanationGetFlg))
+		}
+	}
+
+	page, result, ok := paginate(len(tables), params.StartPosition, params.Limit)
+	if !ok {
+		res.Result = s.noData()
+		return "GET_STATS_LIST", res
+	}
+	res.Result = s.ok()
+	res.DataList = &core.ResponseGetStatsListDataList{
+		Number: len(tables),
+		Result: result,
+		Table:  tables[page.from:page.to],
+	}
+	return "GET_STATS_LIST", res
+}
+
+func matchStatsList(inf core.TableInf, params core.ParamsGetStatsList) bool {
+	if params.StatsCode != 0 && !matchStatsCode(params.StatsCode, inf.StatName.Code, inf.GovOrg.Code) {
+		return false
+	}
+	if params.SearchWord != "" && !matchSearchWord(params.SearchWord, inf.StatName.Name, inf.StatisticsName, inf.Title.Name) {
+		return false
+	}
+	if params.StatsField != "" && string(params.StatsField) != inf.MainCategory.Code && string(params.StatsField) != inf.MainCategory.Code+inf.SubCategory.Code {
+		return false
+	}
+	if params.CollectArea != 0 && collectAreaNames[params.CollectArea] != inf.CollectArea {
+		return false
+	}
+	if params.SearchKind == core.SearchKindSmallArea && inf.SmallArea == 0 {
+		return false
+	}
+	return matchDate(params.SurveyYears, inf.SurveyDate) &&
+		matchDate(params.OpenYears, inf.OpenDate) &&
+		matchDate(params.UpdatedDate, inf.UpdatedDate)
+}
+
+// 政府統計コード (8 桁) または作成機関コード (5 桁) が一致するかを返す。
+func matchStatsCode(code int, statCode string, govOrgCode string) bool {
+	for _, c := range []string{statCode, govOrgCode} {
+		if n, err := strconv.Atoi(c); err == nil && n == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 空白で区切られたすべての語が texts のいずれかに含まれるかを返す。
+func matchSearchWord(word string, texts ...string) bool {
+	for _, w := range strings.Fields(word) {
+		if w == "AND" {
+			continue
+		}
+		found := false
+		for _, text := range texts {
+			if strings.Contains(text, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// 日付 ("2022-08-31"、"201010"、"201501-201512" など) が spec ("yyyy"、"yyyymm-yyyymm" など) の期間と重なるかを返す。
+func matchDate(spec string, date string) bool {
+	if spec == "" {
+		return true
+	}
+	from, to := dateRange(spec)
+	dateFrom, dateTo := dateRange(strings.ReplaceAll(date, "-", ""))
+	if dateFrom == "" {
+		return false
+	}
+	return dateFrom <= to && from <= dateTo
+}
+
+// "2015"、"201501-201512" のような期間を yyyymmdd の範囲にする。
+func dateRange(spec string) (string, string) {
+	from, to, isRange := strings.Cut(spec, "-")
+	if !isRange {
+		to = from
+	}
+	if from != "" {
+		from = (from + "00000000")[:8]
+	}
+	if to == "" {
+		to = "99999999"
+	}
+	return from, (to + "99999999")[:8]
+}
+
+// 統計表の解説を取得しない場合は削除する。
+func explain(inf core.TableInf, flg core.Flag) core.TableInf {
+	if flg == core.FlagNo {
+		inf.Description = core.Description{}
+	}
+	return inf
+}
+
+func explainClass(class core.ClassInf, flg core.Flag) core.ClassInf {
+	if flg != core.FlagNo {
+		return class
+	}
+	objs := make([]core.ClassObj, len(class.ClassObj))
+	for i, obj := range class.ClassObj {
+		obj.Explanation = nil
+		objs[i] = obj
+	}
+	return core.ClassInf{ClassObj: objs}
+}
+
+func commonParams(params url.Values) core.CommonParams {
+	return core.CommonParams{Lang: core.Lang(params.Get("lang"))}
+}
+
+// ページの範囲
+type page struct {
+	from, to int
+}
+
+// startPosition (1 から始まる) と limit から total 件のうち返す範囲と RESULT_INF を返す。該当データがない場合は false を返す。
+//
+// RESULT_INF の TOTAL_NUMBER は getStatsData だけが返すため、ここでは設定しない。
+func paginate(total int, startPosition int, limit int) (page, core.ResultInf, bool) {
+	if startPosition == 0 {
+		startPosition = 1
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if startPosition > total {
+		return page{}, core.ResultInf{}, false
+	}
+
+	p := page{from: startPosition - 1, to: startPosition - 1 + limit}
+	result := core.ResultInf{FromNumber: startPosition}
+	if p.to < total {
+		result.NextKey = p.to + 1
+	} else {
+		p.to = total
+	}
+	result.ToNumber = p.to
+	return p, result, true
+}
+
+// メタ情報取得
+func (s *Server) getMetaInfo(r *request) (string, any) {
+	var params core.ParamsGetMetaInfoList
+	res := core.ResponseGetMetaInfoList{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "GET_META_INFO", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res.Parameter = core.ResponseGetMetaInfoListParameter{CommonParams: commonParams(r.params), ParamsGetMetaInfoList: params}
+
+	t, result := s.table(params.StatsDataId)
+	if t == nil {
+		res.Result = result
+		return "GET_META_INFO", res
+	}
+
+	res.Result = s.ok()
+	res.DataList = core.ResponseGetMetaInfoMetaDataList{
+		Table: explain(t.Inf, params.ExplanationGetFlg),
+		Class: explainClass(t.Class, params.ExplanationGetFlg),
+	}
+	return "GET_META_INFO", res
+}
+
+// 統計表を返す。ない場合はエラーの RESULT を返す。
+func (s *Server) table(statsDataId string) (*Table, core.ResponseResult) {
+	if statsDataId == "" {
+		return nil, s.invalidParameter("必須パラメータ statsDataId が指定されていません。")
+	}
+	t, ok := s.tables[statsDataId]
+	if !ok {
+		return nil, s.invalidParameter("統計表ID %s の統計表は存在しません。", statsDataId)
+	}
+	return t, core.ResponseResult{}
+}
+
+// 統計データの取得条件
+type statsDataQuery struct {
+	statsDataId   string
+	conditions    []core.NarrowingConditon
+	startPosition int
+	limit         int
+
+	metaGetFlg        core.Flag
+	cntGetFlg         core.Flag
+	explanationGetFlg core.Flag
+	annotationGetFlg  core.Flag
+	replaceSpChar     int
+}
+
+// 統計データの取得結果
+type statsDataResult struct {
+	status   int
+	errorMsg string
+	data     core.ResponseGetStatsDataStatisticalData
+}
+
+// 統計データを取得する。
+func (s *Server) queryStatsData(q statsDataQuery) statsDataResult {
+	t, result := s.table(q.statsDataId)
+	if t == nil {
+		return statsDataResult{status: result.Status, errorMsg: result.ErrorMsg}
+	}
+
+	filter, err := newFilter(t.Class, q.conditions)
+	if err != nil {
+		return statsDataResult{status: statusInvalidParameter, errorMsg: err.Error()}
+	}
+
+	var values []core.DataInfValue
+	for _, v := range t.Values {
+		if filter.match(v) {
+			values = append(values, v)
+		}
+	}
+
+	res := statsDataResult{status: core.ResultStatusOK, errorMsg: msgOK}
+	res.data.Number = len(values)
+	res.data.Table = explain(t.Inf, q.explanationGetFlg)
+
+	if q.cntGetFlg == core.FlagYes {
+		res.data.Result = core.ResultInf{TotalNumber: len(values)}
+		return res
+	}
+
+	p, resultInf, ok := paginate(len(values), q.startPosition, q.limit)
+	if !ok {
+		return statsDataResult{status: core.ResultStatusNoData, errorMsg: msgNoData, data: res.data}
+	}
+	// getStatsList と getDataCatalog は件数を NUMBER だけで返すが、getStatsData は RESULT_INF に
+	// 絞り込み後の件数を TOTAL_NUMBER として返す
+	resultInf.TotalNumber = len(values)
+	res.data.Result = resultInf
+
+	if q.metaGetFlg != core.FlagNo {
+		res.data.Class = explainClass(filter.class(t.Class), q.explanationGetFlg)
+	}
+
+	res.data.Data.Note = t.Notes
+	if q.annotationGetFlg != core.FlagNo {
+		res.data.Data.Annotation = t.Annotations
+	}
+	res.data.Data.Value = make([]core.DataInfValue, 0, p.to-p.from)
+	for _, v := range values[p.from:p.to] {
+		if q.annotationGetFlg == core.FlagNo {
+			v.Annotation = ""
+		}
+		v.Value = replaceSpChar(v.Value, t.Notes, q.replaceSpChar)
+		res.data.Data.Value = append(res.data.Data.Value, v)
+	}
+	return res
+}
+
+// 特殊文字 (NOTE の char) を置換する。
+func replaceSpChar(value string, notes []core.DataInfNote, mode int) string {
+	if mode == core.ReplaceSpCharNone {
+		return value
+	}
+	for _, note := range notes {
+		if note.Char != value {
+			continue
+		}
+		switch mode {
+		case core.ReplaceSpCharZero:
+			return "0"
+		case core.ReplaceSpCharNull:
+			return ""
+		case core.ReplaceSpCharNA:
+			return "NA"
+		}
+	}
+	return value
+}
+
+// 統計データ取得
+func (s *Server) getStatsData(r *request) (string, any) {
+	var params core.ParamsGetStatsData
+	res := core.ResponseGetStatsData{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "GET_STATS_DATA", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	params.DataSetID = r.params.Get("dataSetId")
+	res.Parameter = core.ResponseGetStatsDataParameter{CommonParams: commonParams(r.params), ParamsGetStatsData: params}
+
+	q := statsDataQuery{
+		statsDataId:       params.StatsDataId,
+		conditions:        []core.NarrowingConditon{params.NarrowingConditon},
+		startPosition:     params.StartPosition,
+		limit:             params.Limit,
+		metaGetFlg:        params.MetaGetFlg,
+		cntGetFlg:         params.CntGetFlg,
+		explanationGetFlg: params.ExplanationGetFlg,
+		annotationGetFlg:  params.AnnotationGetFlg,
+		replaceSpChar:     params.ReplaceSpChar,
+	}
+	if params.DataSetID != "" {
+		d, ok := s.datasets[params.DataSetID]
+		if !ok {
+			res.Result = s.invalidParameter("データセットID %s のデータセットは存在しません。", params.DataSetID)
+			return "GET_STATS_DATA", res
+		}
+		q.statsDataId = d.StatsDataId
+		q.conditions = append(q.conditions, d.Condition)
+	}
+
+	result := s.queryStatsData(q)
+	res.Result = s.result(result.status, result.errorMsg)
+	res.DataList = result.data
+	return "GET_STATS_DATA", res
+}
+
+// 統計データ一括取得
+func (s *Server) getStatsDatas(r *request) (string, any) {
+	var params core.ParamsGetStatsDatas
+	res := core.ResponseGetStatsDatas{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "GET_STATS_DATAS", res
+	}
+	res.ParameterList = core.ResponseGetStatsParameterList{CommonParams: commonParams(r.params), ParamsGetStatsDatas: params}
+
+	var specs []core.StatsDatasSpec
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &specs)
+	}
+	if err != nil {
+		res.Result = s.invalidParameter("リクエストボディが不正です。")
+		return "GET_STATS_DATAS", res
+	}
+	if len(specs) == 0 || len(specs) > core.MaxStatsDatasSpecs {
+		res.Result = s.invalidParameter("statsDatasSpec は 1 件以上 %d 件以下で指定して下さい。", core.MaxStatsDatasSpecs)
+		return "GET_STATS_DATAS", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := &res.StatisticalDataList
+	failed := 0
+	for i, spec := range specs {
+		no := i + 1
+		res.ParameterList.Parameter = append(res.ParameterList.Parameter, core.ResponseGetStatsParameter{RequestNumber: no, StatsDatasSpec: spec})
+
+		result := s.queryStatsData(statsDataQuery{
+			statsDataId:       spec.StatsDataId,
+			conditions:        []core.NarrowingConditon{spec.NarrowingConditon},
+			startPosition:     spec.StartPosition,
+			limit:             spec.Limit,
+			metaGetFlg:        params.MetaGetFlg,
+			explanationGetFlg: params.ExplanationGetFlg,
+			annotationGetFlg:  params.AnnotationGetFlg,
+			replaceSpChar:     params.ReplaceSpChar,
+		})
+		list.ResultInfList.ResultInf = append(list.ResultInfList.ResultInf, core.StatsDatasResultInf{
+			RequestNumber: no,
+			Status:        result.status,
+			ErrorMsg:      result.errorMsg,
+			ResultInf:     result.data.Result,
+		})
+		if result.status != core.ResultStatusOK {
+			failed++
+			continue
+		}
+		list.TableInfList.TableInf = append(list.TableInfList.TableInf, core.StatsDatasTableInf{RequestNumber: no, TableInf: result.data.Table})
+		if len(result.data.Class.ClassObj) > 0 {
+			list.ClassInfList.ClassInf = append(list.ClassInfList.ClassInf, core.StatsDatasClassInf{RequestNumber: no, ClassInf: result.data.Class})
+		}
+		list.DataInfList.DataInf = append(list.DataInfList.DataInf, core.StatsDatasDataInf{RequestNumber: no, DataInf: result.data.Data})
+	}
+
+	if failed > 0 {
+		res.Result = s.result(core.ResultStatusPartialError, msgPartialError)
+	} else {
+		res.Result = s.ok()
+	}
+	return "GET_STATS_DATAS", res
+}
+
+// データセット登録
+func (s *Server) postDataset(r *request) (string, any) {
+	var params core.ParamsPostDataset
+	res := core.ResponsePostDataset{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "POST_DATASET", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res.Parameter = core.ResponsePostDatasetParameter{
+		CommonParams:      commonParams(r.params),
+		DataSetID:         params.DataSetID,
+		StatsDataId:       params.StatsDataId,
+		NarrowingConditon: params.NarrowingConditon,
+		OpenSpecified:     string(params.OpenSpecified),
+		ProcessMode:       string(params.ProcessMode),
+		DataSetName:       params.DataSetName,
+	}
+
+	switch params.ProcessMode {
+	case core.ProcessModeDelete:
+		if _, ok := s.datasets[params.DataSetID]; !ok {
+			res.Result = s.invalidParameter("データセットID %s のデータセットは存在しません。", params.DataSetID)
+			return "POST_DATASET", res
+		}
+		s.deleteDataset(params.DataSetID)
+		res.Result = s.ok()
+		res.RefistInf = core.ResponsePostDatasetRegistInf{Mode: "delete", DatasetId: params.DataSetID}
+		return "POST_DATASET", res
+
+	case "", core.ProcessModeRegister:
+	default:
+		res.Result = s.invalidParameter("パラメータ processMode が不正です。")
+		return "POST_DATASET", res
+	}
+
+	mode := "add"
+	d := Dataset{
+		ID:          params.DataSetID,
+		StatsDataId: params.StatsDataId,
+		Name:        params.DataSetName,
+		Public:      params.OpenSpecified == core.OpenSpecifiedPublic,
+		Condition:   params.NarrowingConditon,
+	}
+	if old, ok := s.datasets[d.ID]; ok {
+		mode = "update"
+		if d.StatsDataId == "" {
+			d.StatsDataId = old.StatsDataId
+		}
+		if d.Name == "" {
+			d.Name = old.Name
+		}
+	}
+
+	t, result := s.table(d.StatsDataId)
+	if t == nil {
+		res.Result = result
+		return "POST_DATASET", res
+	}
+	filter, err := newFilter(t.Class, []core.NarrowingConditon{d.Condition})
+	if err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "POST_DATASET", res
+	}
+	if d.ID == "" {
+		d.ID = s.newDatasetID(t.Inf.StatName.Code)
+	}
+	s.putDataset(d)
+
+	res.Result = s.ok()
+	res.RefistInf = core.ResponsePostDatasetRegistInf{
+		Mode:        mode,
+		DatasetId:   d.ID,
+		StatsDataId: d.StatsDataId,
+		PublicState: publicState(d.Public, false),
+		TotalNumber: filter.count(t.Values),
+	}
+	return "POST_DATASET", res
+}
+
+// "00200522-20221103214310" のようなデータセット ID を返す。
+func (s *Server) newDatasetID(statCode string) string {
+	base := statCode + "-" + s.now().In(jst).Format("20060102150405")
+	id := base
+	for n := 2; ; n++ {
+		if _, ok := s.datasets[id]; !ok {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// PUBLIC_STATE はデータセット登録では小文字、データセット参照では大文字で出力される。
+func publicState(public bool, upper bool) string {
+	state := "no"
+	if public {
+		state = "yes"
+	}
+	if upper {
+		return strings.ToUpper(state)
+	}
+	return state
+}
+
+// データセット参照
+//
+// dataSetId を指定しない場合はデータセットの一覧 (GET_DATASET_LIST) を返す。
+func (s *Server) refDataset(r *request) (string, any) {
+	if r.params.Get("dataSetId") == "" {
+		return s.getDatasetList(r)
+	}
+
+	var params core.ParamsRefDataset
+	res := core.ResponseRefDataset{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "REF_DATASET", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res.Parameter = core.ResponseRefDatasetParameter{CommonParams: commonParams(r.params), ParamsRefDataset: params}
+
+	d, ok := s.datasets[params.DataSetID]
+	if !ok {
+		res.Result = s.invalidParameter("データセットID %s のデータセットは存在しません。", params.DataSetID)
+		return "REF_DATASET", res
+	}
+	res.Result = s.ok()
+	res.Dataset = s.datasetInf(d, params.ExplanationGetFlg)
+	return "REF_DATASET", res
+}
+
+func (s *Server) getDatasetList(r *request) (string, any) {
+	var params core.ParamsGetDatasetList
+	res := core.ResponseGetDatasetList{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "GET_DATASET_LIST", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res.Parameter = core.ResponseRefDatasetParameter{
+		CommonParams:     commonParams(r.params),
+		ParamsRefDataset: core.ParamsRefDataset{ExplanationGetFlg: params.ExplanationGetFlg},
+	}
+
+	for _, id := range s.datasetIDs {
+		inf := s.datasetInf(s.datasets[id], params.ExplanationGetFlg)
+		if params.CollectArea != 0 && collectAreaNames[params.CollectArea] != inf.TableInf.CollectArea {
+			continue
+		}
+		res.DatasetList.Dataset = append(res.DatasetList.Dataset, inf)
+	}
+	res.DatasetList.Number = len(res.DatasetList.Dataset)
+	if res.DatasetList.Number == 0 {
+		res.Result = s.noData()
+	} else {
+		res.Result = s.ok()
+	}
+	return "GET_DATASET_LIST", res
+}
+
+func (s *Server) datasetInf(d *Dataset, flg core.Flag) core.ResponseRefDatasetInf {
+	inf := core.ResponseRefDatasetInf{
+		ID:          d.ID,
+		DataSetName: d.Name,
+		PublicState: publicState(d.Public, true),
+	}
+	if t, ok := s.tables[d.StatsDataId]; ok {
+		inf.TableInf = explain(t.Inf, flg)
+		if filter, err := newFilter(t.Class, []core.NarrowingConditon{d.Condition}); err == nil {
+			inf.Result.TotalNumber = filter.count(t.Values)
+		}
+	}
+	return inf
+}
+
+// データカタログ情報取得
+func (s *Server) getDataCatalog(r *request) (string, any) {
+	var params core.ParamsGetDataCatalog
+	res := core.ResponseGetDataCatalog{}
+	if err := decodeParams(r.params, &params); err != nil {
+		res.Result = s.invalidParameter("%v", err)
+		return "GET_DATA_CATALOG", res
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res.Parameter = core.ResponseGetDataCatalogParameter{CommonParams: commonParams(r.params), ParamsGetDataCatalog: params}
+
+	var catalogs []core.DataCatalogInf
+	for _, c := range s.catalogs {
+		if c, ok := matchDataCatalog(c, params); ok {
+			catalogs = append(catalogs, c)
+		}
+	}
+
+	page, result, ok := paginate(len(catalogs), params.StartPosition, params.Limit)
+	if !ok {
+		res.Result = s.noData()
+		return "GET_DATA_CATALOG", res
+	}
+	res.Result = s.ok()
+	res.DataCatalogList = core.DataCatalogListInf{
+		Number:      len(catalogs),
+		Result:      result,
+		DataCatalog: catalogs[page.from:page.to],
+	}
+	return "GET_DATA_CATALOG", res
+}
+
+// 条件に一致するデータカタログを返す。dataType と resourceId を指定した場合は一致するリソースだけを返す。
+func matchDataCatalog(c core.DataCatalogInf, params core.ParamsGetDataCatalog) (core.DataCatalogInf, bool) {
+	ds := c.Dataset
+	if params.CatalogId != 0 {
+		if n, err := strconv.Atoi(c.ID); err != nil || n != params.CatalogId {
+			return c, false
+		}
+	}
+	if params.StatsCode != 0 && !matchStatsCode(params.StatsCode, ds.StatName.Code, ds.Organization.Code) {
+		return c, false
+	}
+	if params.SearchWord != "" && !matchSearchWord(params.SearchWord, ds.StatName.Name, ds.Title.Name) {
+		return c, false
+	}
+	if params.CollectArea != 0 && collectAreaNames[params.CollectArea] != ds.Title.CollectArea {
+		return c, false
+	}
+	if !matchDate(params.SurveyYears, ds.Title.SurveyDate) ||
+		!matchDate(params.OpenYears, ds.ReleaseDate) ||
+		!matchDate(params.UpdatedDate, ds.LastModifiedDate) {
+		return c, false
+	}
+	if params.ExplanationGetFlg == core.FlagNo {
+		c.Dataset.Description = core.Description{}
+	}
+
+	if params.DataType == "" && params.ResourceId == 0 {
+		return c, true
+	}
+	var resources []core.Resources
+	for _, r := range c.Resources {
+		if params.DataType != "" && r.Resource.Format != params.DataType {
+			continue
+		}
+		if params.ResourceId != 0 {
+			if n, err := strconv.Atoi(r.Resource.ID); err != nil || n != params.ResourceId {
+				continue
+			}
+		}
+		resources = append(resources, r)
+	}
+	c.Resources = resources
+	return c, len(resources) > 0
+}
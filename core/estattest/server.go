@@ -0,0 +1,384 @@
+// e-Stat API (バージョン 3.0) を模したテスト用の HTTP サーバです。
+//
+// 統計表、メタ情報、統計データをメモリ上に登録し、実際の e-Stat と同じ形式の XML、JSON で返します。
+// 絞り込み条件、startPosition と limit によるページ送り、データセットの登録、
+// エラーや遅延の注入に対応しているため、ApiClient を使うコードをネットワークなしでテストできます。
+//
+//	srv := estattest.NewServer()
+//	defer srv.Close()
+//	srv.AddTable(estattest.Table{Inf: core.TableInf{ID: "0003109741"}, Values: values})
+//	ac := srv.ApiClient()
+//	data, err := ac.GetStatsData(ctx, core.ParamsGetStatsData{StatsDataId: "0003109741"})
+package estattest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+// RESULT の ERROR_MSG
+const (
+	msgOK           = "正常に終了しました。"
+	msgNoData       = "正常に終了しましたが、該当データはありませんでした。"
+	msgPartialError = "正常に終了しましたが、一部にエラーがあります。"
+	msgInvalidAppID = "認証に失敗しました。アプリケーションIDを確認して下さい。"
+)
+
+// パラメータが不正な場合の STATUS
+const statusInvalidParameter = 101
+
+// e-Stat の日時のタイムゾーン
+var jst = time.FixedZone("JST", 9*60*60)
+
+// 統計表
+type Table struct {
+	// 統計表情報。OverallTotalNumber が 0 の場合は Values の件数を使います。
+	Inf core.TableInf
+
+	// メタ情報
+	Class core.ClassInf
+
+	// 特殊文字の注記と、注釈
+	Notes       []core.DataInfNote
+	Annotations []core.DataInfAnnotation
+
+	// 統計データ
+	Values []core.DataInfValue
+}
+
+// 登録されたデータセット
+type Dataset struct {
+	ID          string
+	StatsDataId string
+	Name        string
+	Public      bool
+
+	// 絞り込み条件
+	Condition core.NarrowingConditon
+}
+
+// 注入するエラー
+type Fault struct {
+	// HTTP のステータスコード。Status が 0 の場合はレスポンスボディを e-Stat の形式にしません。
+	//
+	// HTTPStatus と Status がともに 0 の場合は Latency だけを注入します。
+	HTTPStatus int
+
+	// RESULT の STATUS と ERROR_MSG
+	Status   int
+	ErrorMsg string
+
+	// レスポンスを返すまでの遅延
+	Latency time.Duration
+
+	// 注入する回数。0 の場合は ClearFaults まで注入し続けます。
+	Times int
+}
+
+// テスト用の e-Stat API サーバ
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	appID   string
+	latency time.Duration
+	now     func() time.Time
+
+	tables     map[string]*Table
+	tableIDs   []string
+	catalogs   []core.DataCatalogInf
+	datasets   map[string]*Dataset
+	datasetIDs []string
+
+	faults   map[string][]*Fault
+	requests map[string]int
+}
+
+type Option func(*Server)
+
+// appId を検証します。異なる appId のリクエストには STATUS 100 を返します。
+func WithAppID(appID string) Option {
+	return func(s *Server) {
+		s.appID = appID
+	}
+}
+
+// すべてのレスポンスを返すまでの遅延を指定します。
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// RESULT の DATE とデータセット ID に使う現在時刻を指定します。
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// 起動した Server を返します。使い終わったら Close を呼んで下さい。
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		now:      time.Now,
+		tables:   map[string]*Table{},
+		datasets: map[string]*Dataset{},
+		faults:   map[string][]*Fault{},
+		requests: map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Server にリクエストする IHttpClient を返します。
+func (s *Server) Client(opts ...core.Option) core.IHttpClient {
+	return core.New(append([]core.Option{core.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// Server にリクエストする IApiClient を返します。WithAppID を指定した場合はその appId を使います。
+func (s *Server) ApiClient(opts ...core.ApiOption) core.IApiClient {
+	return core.NewApiClient(s.Client(), core.CommonParams{AppID: s.appID}, opts...)
+}
+
+// 統計表を登録します。同じ ID の統計表は置き換えます。
+func (s *Server) AddTable(t Table) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.Inf.OverallTotalNumber == 0 {
+		t.Inf.OverallTotalNumber = len(t.Values)
+	}
+	if _, ok := s.tables[t.Inf.ID]; !ok {
+		s.tableIDs = append(s.tableIDs, t.Inf.ID)
+	}
+	s.tables[t.Inf.ID] = &t
+}
+
+// データカタログを登録します。
+func (s *Server) AddDataCatalog(c core.DataCatalogInf) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.catalogs = append(s.catalogs, c)
+}
+
+// データセットを登録します。同じ ID のデータセットは置き換えます。
+func (s *Server) AddDataset(d Dataset) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putDataset(d)
+}
+
+// 登録されているデータセットを返します。
+func (s *Server) Dataset(id string) (Dataset, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.datasets[id]
+	if !ok {
+		return Dataset{}, false
+	}
+	return *d, true
+}
+
+func (s *Server) putDataset(d Dataset) {
+	if _, ok := s.datasets[d.ID]; !ok {
+		s.datasetIDs = append(s.datasetIDs, d.ID)
+	}
+	s.datasets[d.ID] = &d
+}
+
+func (s *Server) deleteDataset(id string) {
+	delete(s.datasets, id)
+	for i, v := range s.datasetIDs {
+		if v == id {
+			s.datasetIDs = append(s.datasetIDs[:i], s.datasetIDs[i+1:]...)
+			break
+		}
+	}
+}
+
+// endpoint ("/getStatsData" など) にエラーを注入します。
+//
+// JSON 形式のパス ("/json/getStatsData") にも適用されます。複数注入した場合は注入した順に使います。
+func (s *Server) InjectFault(endpoint string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults[endpoint] = append(s.faults[endpoint], &f)
+}
+
+// 注入したエラーをすべて削除します。
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = map[string][]*Fault{}
+}
+
+// すべてのレスポンスを返すまでの遅延を変更します。
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// endpoint ("/getStatsData" など) へのリクエストの回数を返します。JSON 形式のパスへのリクエストも含みます。
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[endpoint]
+}
+
+// API の処理
+type handler struct {
+	// ルート要素
+	root string
+
+	// パラメータとリクエストボディを読み込んでから s.mu を取得して処理する
+	handle func(s *Server, r *request) (string, any)
+}
+
+var handlers = map[string]handler{
+	"/getStatsList":   {root: "GET_STATS_LIST", handle: (*Server).getStatsList},
+	"/getMetaInfo":    {root: "GET_META_INFO", handle: (*Server).getMetaInfo},
+	"/getStatsData":   {root: "GET_STATS_DATA", handle: (*Server).getStatsData},
+	"/getStatsDatas":  {root: "GET_STATS_DATAS", handle: (*Server).getStatsDatas},
+	"/postDataset":    {root: "POST_DATASET", handle: (*Server).postDataset},
+	"/refDataset":     {root: "REF_DATASET", handle: (*Server).refDataset},
+	"/getDataCatalog": {root: "GET_DATA_CATALOG", handle: (*Server).getDataCatalog},
+}
+
+// リクエスト
+type request struct {
+	*http.Request
+	params url.Values
+}
+
+// RESULT だけのレスポンス
+type resultResponse struct {
+	Result core.ResponseResult `xml:"RESULT"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	jsonFormat := strings.HasPrefix(path, "/json/")
+	if jsonFormat {
+		path = strings.TrimPrefix(path, "/json")
+	}
+	h, ok := handlers[path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests[path]++
+	latency := s.latency
+	fault := s.takeFault(path)
+	s.mu.Unlock()
+
+	if fault != nil {
+		latency += fault.Latency
+	}
+	if err := sleep(r.Context(), latency); err != nil {
+		return
+	}
+
+	write := func(httpStatus int, root string, v any) {
+		if jsonFormat {
+			w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "application/xml;charset=utf-8")
+		}
+		w.WriteHeader(httpStatus)
+		w.Write(encodeResponse(root, v, jsonFormat))
+	}
+
+	if fault != nil && (fault.HTTPStatus != 0 || fault.Status != core.ResultStatusOK) {
+		httpStatus := fault.HTTPStatus
+		if httpStatus == 0 {
+			httpStatus = http.StatusOK
+		}
+		if fault.Status == core.ResultStatusOK {
+			http.Error(w, http.StatusText(httpStatus), httpStatus)
+			return
+		}
+		write(httpStatus, h.root, resultResponse{Result: s.result(fault.Status, fault.ErrorMsg)})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		write(http.StatusOK, h.root, resultResponse{Result: s.result(statusInvalidParameter, err.Error())})
+		return
+	}
+	if s.appID != "" && r.Form.Get("appId") != s.appID {
+		write(http.StatusOK, h.root, resultResponse{Result: s.result(core.ResultStatusInvalidAppID, msgInvalidAppID)})
+		return
+	}
+
+	root, v := h.handle(s, &request{Request: r, params: r.Form})
+
+	write(http.StatusOK, root, v)
+}
+
+// 注入するエラーを取り出す。s.mu を保持して呼ぶ。
+func (s *Server) takeFault(endpoint string) *Fault {
+	faults := s.faults[endpoint]
+	if len(faults) == 0 {
+		return nil
+	}
+	f := *faults[0]
+	if faults[0].Times > 0 {
+		faults[0].Times--
+		if faults[0].Times == 0 {
+			s.faults[endpoint] = faults[1:]
+		}
+	}
+	return &f
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (s *Server) result(status int, msg string) core.ResponseResult {
+	return core.ResponseResult{Status: status, ErrorMsg: msg, Date: s.now().In(jst)}
+}
+
+func (s *Server) ok() core.ResponseResult {
+	return s.result(core.ResultStatusOK, msgOK)
+}
+
+func (s *Server) noData() core.ResponseResult {
+	return s.result(core.ResultStatusNoData, msgNoData)
+}
+
+func (s *Server) invalidParameter(format string, args ...any) core.ResponseResult {
+	return s.result(statusInvalidParameter, fmt.Sprintf(format, args...))
+}
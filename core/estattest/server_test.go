@@ -0,0 +1,479 @@
+package estattest_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+)
+
+var formats = []struct {
+	name   string
+	format core.Format
+}{
+	{name: "XML", format: core.FormatXML},
+	{name: "JSON", format: core.FormatJSON},
+}
+
+var testTableInf = core.TableInf{
+	ID:                 "0003109741",
+	StatName:           core.StatName{Code: "00100409", Name: "国民経済計算"},
+	GovOrg:             core.GovOrg{Code: "00100", Name: "内閣府"},
+	StatisticsName:     "四半期別GDP速報",
+	Title:              core.Title{Number: "1", Name: "名目季節調整系列"},
+	Cycle:              "四半期",
+	SurveyDate:         "202204-202206",
+	OpenDate:           "2022-09-08",
+	CollectArea:        "全国",
+	MainCategory:       core.MainCategory{Code: "07", Name: "企業・家計・経済"},
+	SubCategory:        core.SubCategory{Code: "03", Name: "国民経済計算"},
+	UpdatedDate:        "2022-09-16",
+	OverallTotalNumber: 6,
+}
+
+var testClass = core.ClassInf{
+	ClassObj: []core.ClassObj{
+		{
+			ID:   "cat01",
+			Name: "国内総生産",
+			Class: []core.ClassObjClass{
+				{Code: "11", Name: "国内総生産(支出側)", Level: "1", Unit: "十億円"},
+				{Code: "12", Name: "民間最終消費支出", Level: "2", Unit: "十億円", ParentCode: "11"},
+				{Code: "13", Name: "家計最終消費支出", Level: "3", Unit: "十億円", ParentCode: "12"},
+			},
+		},
+		{
+			ID:   "time",
+			Name: "時間軸(四半期)",
+			Class: []core.ClassObjClass{
+				{Code: "2022000406", Name: "2022年4-6月期", Level: "1"},
+				{Code: "2022000709", Name: "2022年7-9月期", Level: "1"},
+			},
+		},
+	},
+}
+
+var testValues = []core.DataInfValue{
+	{Cat01: "11", Time: "2022000406", Unit: "十億円", Value: "543021"},
+	{Cat01: "11", Time: "2022000709", Unit: "十億円", Value: "-"},
+	{Cat01: "12", Time: "2022000406", Unit: "十億円", Value: "295012"},
+	{Cat01: "12", Time: "2022000709", Unit: "十億円", Value: "296458"},
+	{Cat01: "13", Time: "2022000406", Unit: "十億円", Value: "287613", Annotation: "1"},
+	{Cat01: "13", Time: "2022000709", Unit: "十億円", Value: "288777"},
+}
+
+func newServer(t *testing.T, opts ...estattest.Option) *estattest.Server {
+	t.Helper()
+
+	srv := estattest.NewServer(opts...)
+	t.Cleanup(srv.Close)
+	srv.AddTable(estattest.Table{
+		Inf:         testTableInf,
+		Class:       testClass,
+		Notes:       []core.DataInfNote{{Char: "-", Note: "数値が得られないもの"}},
+		Annotations: []core.DataInfAnnotation{{Target: "1", Annotation: "速報値"}},
+		Values:      testValues,
+	})
+	return srv
+}
+
+func TestGetStatsData(t *testing.T) {
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			ac := newServer(t).ApiClient(core.WithFormat(f.format))
+
+			data, err := ac.GetStatsData(context.Background(), core.ParamsGetStatsData{
+				StatsDataId: testTableInf.ID,
+				NarrowingConditon: core.NarrowingConditon{
+					CategoryCondition: core.CategoryCondition{LevelCat01: "2-"},
+					TimeCondition:     core.TimeCondition{CodeTime: "2022000709"},
+				},
+				ReplaceSpChar: core.ReplaceSpCharNA,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := core.ResultInf{TotalNumber: 2, FromNumber: 1, ToNumber: 2}
+			if got := data.DataList.Result; !reflect.DeepEqual(got, want) {
+				t.Errorf("Result = %+v, want %+v", got, want)
+			}
+			var codes []string
+			for _, v := range data.DataList.Data.Value {
+				codes = append(codes, v.Cat01+"="+v.Value)
+			}
+			if want := []string{"12=296458", "13=288777"}; !reflect.DeepEqual(codes, want) {
+				t.Errorf("values = %v, want %v", codes, want)
+			}
+			if got := data.DataList.Table; !reflect.DeepEqual(got, testTableInf) {
+				t.Errorf("Table = %+v, want %+v", got, testTableInf)
+			}
+			if got := len(data.DataList.Class.ClassObj[0].Class); got != 2 {
+				t.Errorf("len(cat01 classes) = %d, want 2", got)
+			}
+			if got := data.DataList.Data.Note; !reflect.DeepEqual(got, []core.DataInfNote{{Char: "-", Note: "数値が得られないもの"}}) {
+				t.Errorf("Note = %+v", got)
+			}
+		})
+	}
+}
+
+func TestGetStatsDataReplaceSpChar(t *testing.T) {
+	ac := newServer(t).ApiClient()
+
+	data, err := ac.GetStatsData(context.Background(), core.ParamsGetStatsData{
+		StatsDataId:       testTableInf.ID,
+		NarrowingConditon: core.NarrowingConditon{CategoryCondition: core.CategoryCondition{CodeCat01: "11"}},
+		MetaGetFlg:        core.FlagNo,
+		AnnotationGetFlg:  core.FlagNo,
+		ReplaceSpChar:     core.ReplaceSpCharNA,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := data.DataList.Data.Value[1].Value; got != "NA" {
+		t.Errorf("Value = %q, want NA", got)
+	}
+	if got := data.DataList.Class.ClassObj; got != nil {
+		t.Errorf("ClassObj = %+v, want nil", got)
+	}
+	if got := data.DataList.Data.Annotation; got != nil {
+		t.Errorf("Annotation = %+v, want nil", got)
+	}
+}
+
+func TestIterateStatsData(t *testing.T) {
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			srv := newServer(t)
+			ac := srv.ApiClient(core.WithFormat(f.format))
+
+			it := ac.IterateStatsData(context.Background(), core.ParamsGetStatsData{StatsDataId: testTableInf.ID, Limit: 4})
+			var values []core.DataInfValue
+			for it.Next() {
+				values = append(values, it.Value())
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(values, testValues) {
+				t.Errorf("values = %+v, want %+v", values, testValues)
+			}
+			if got := srv.Requests("/getStatsData"); got != 2 {
+				t.Errorf("Requests = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestGetStatsDataCount(t *testing.T) {
+	ac := newServer(t).ApiClient()
+
+	data, err := ac.GetStatsData(context.Background(), core.ParamsGetStatsData{StatsDataId: testTableInf.ID, CntGetFlg: core.FlagYes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.DataList.Result.TotalNumber != len(testValues) || data.DataList.Data.Value != nil {
+		t.Errorf("DataList = %+v", data.DataList)
+	}
+
+	_, err = ac.GetStatsData(context.Background(), core.ParamsGetStatsData{
+		StatsDataId:       testTableInf.ID,
+		NarrowingConditon: core.NarrowingConditon{CategoryCondition: core.CategoryCondition{CodeCat01: "99"}},
+	})
+	if !errors.Is(err, core.ErrNoData) {
+		t.Errorf("err = %v, want ErrNoData", err)
+	}
+}
+
+func TestGetStatsList(t *testing.T) {
+	srv := newServer(t)
+	for i, id := range []string{"0003000001", "0003000002", "0003000003"} {
+		inf := testTableInf
+		inf.ID = id
+		inf.UpdatedDate = []string{"2021-01-05", "2022-06-30", "2022-10-01"}[i]
+		srv.AddTable(estattest.Table{Inf: inf})
+	}
+
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			ac := srv.ApiClient(core.WithFormat(f.format))
+
+			pager := ac.NewStatsListPager(core.ParamsGetStatsList{StatsCode: 100409, UpdatedDate: "20220601-", Limit: 1}, 0)
+			tables, err := pager.All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, inf := range tables {
+				ids = append(ids, inf.ID)
+			}
+			if want := []string{"0003109741", "0003000002", "0003000003"}; !reflect.DeepEqual(ids, want) {
+				t.Errorf("ids = %v, want %v", ids, want)
+			}
+			if got := pager.Total(); got != 3 {
+				t.Errorf("Total = %d, want 3", got)
+			}
+
+			list, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{StatsCode: 100409, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := (core.ResultInf{FromNumber: 1, ToNumber: 1, NextKey: 2}); list.DataList.Result != want {
+				t.Errorf("RESULT_INF = %+v, want %+v", list.DataList.Result, want)
+			}
+
+			_, err = ac.GetStatsList(context.Background(), core.ParamsGetStatsList{SearchWord: "存在しない"})
+			if !errors.Is(err, core.ErrNoData) {
+				t.Errorf("err = %v, want ErrNoData", err)
+			}
+		})
+	}
+}
+
+func TestGetMetaInfo(t *testing.T) {
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			ac := newServer(t).ApiClient(core.WithFormat(f.format))
+
+			meta, err := ac.GetMetaInfoList(context.Background(), core.ParamsGetMetaInfoList{StatsDataId: testTableInf.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(meta.DataList.Class, testClass) {
+				t.Errorf("Class = %+v, want %+v", meta.DataList.Class, testClass)
+			}
+
+			_, err = ac.GetMetaInfoList(context.Background(), core.ParamsGetMetaInfoList{StatsDataId: "0000000000"})
+			if !errors.Is(err, core.ErrInvalidParameter) {
+				t.Errorf("err = %v, want ErrInvalidParameter", err)
+			}
+		})
+	}
+}
+
+func TestJSONTypes(t *testing.T) {
+	srv := newServer(t)
+
+	query := core.ParamsGetMetaInfoListRoot{ParamsGetMetaInfoList: core.ParamsGetMetaInfoList{StatsDataId: testTableInf.ID}}
+	_, body, err := srv.Client().Get(context.Background(), "/json/getMetaInfo", query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		GetMetaInfo struct {
+			MetadataInf struct {
+				TableInf map[string]any `json:"TABLE_INF"`
+			} `json:"METADATA_INF"`
+		} `json:"GET_META_INFO"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	// 数字だけのコードも文字列のフィールドは文字列、整数のフィールドは数値で出力する
+	table := resp.GetMetaInfo.MetadataInf.TableInf
+	if got := table["TITLE"].(map[string]any)["@no"]; got != "1" {
+		t.Errorf("TITLE.@no = %#v, want \"1\"", got)
+	}
+	if got := table["SURVEY_DATE"]; got != "202204-202206" {
+		t.Errorf("SURVEY_DATE = %#v", got)
+	}
+	if got := table["OVERALL_TOTAL_NUMBER"]; got != float64(6) {
+		t.Errorf("OVERALL_TOTAL_NUMBER = %#v, want 6", got)
+	}
+}
+
+func TestGetStatsDatas(t *testing.T) {
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			ac := newServer(t).ApiClient(core.WithFormat(f.format))
+
+			results, err := ac.GetStatsDatasAll(context.Background(), core.ParamsGetStatsDatas{}, []core.StatsDatasSpec{
+				{StatsDataId: testTableInf.ID, NarrowingConditon: core.NarrowingConditon{CategoryCondition: core.CategoryCondition{CodeCat01: "12"}}},
+				{StatsDataId: "0000000000"},
+			})
+			var statsDatasErr *core.StatsDatasError
+			if !errors.As(err, &statsDatasErr) {
+				t.Fatalf("err = %v, want *StatsDatasError", err)
+			}
+			if got := len(results[0].StatisticalData.Data.Value); got != 2 {
+				t.Errorf("len(values) = %d, want 2", got)
+			}
+			if results[0].Err != nil || !errors.Is(results[1].Err, core.ErrInvalidParameter) {
+				t.Errorf("errs = %v, %v", results[0].Err, results[1].Err)
+			}
+		})
+	}
+}
+
+func TestDataset(t *testing.T) {
+	now := time.Date(2022, time.November, 3, 21, 43, 10, 0, time.UTC)
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			srv := newServer(t, estattest.WithClock(func() time.Time { return now }))
+			ac := srv.ApiClient(core.WithFormat(f.format))
+			ctx := context.Background()
+
+			posted, err := ac.PostDataset(ctx, core.ParamsPostDataset{
+				StatsDataId:       testTableInf.ID,
+				NarrowingConditon: core.NarrowingConditon{CategoryCondition: core.CategoryCondition{CodeCat01From: "12", CodeCat01To: "13"}},
+				OpenSpecified:     core.OpenSpecifiedPublic,
+				DataSetName:       "消費支出",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := core.ResponsePostDatasetRegistInf{
+				Mode:        "add",
+				DatasetId:   "00100409-20221104064310",
+				StatsDataId: testTableInf.ID,
+				PublicState: "yes",
+				TotalNumber: 4,
+			}
+			if got := posted.RefistInf; !reflect.DeepEqual(got, want) {
+				t.Errorf("RegistInf = %+v, want %+v", got, want)
+			}
+
+			ref, err := ac.RefDataset(ctx, core.ParamsRefDataset{DataSetID: want.DatasetId})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ref.Dataset; got.DataSetName != "消費支出" || got.PublicState != "YES" || got.Result.TotalNumber != 4 || got.TableInf.ID != testTableInf.ID {
+				t.Errorf("Dataset = %+v", got)
+			}
+
+			list, err := ac.GetDatasetList(ctx, core.ParamsGetDatasetList{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := list.DatasetList.Number; got != 1 {
+				t.Errorf("Number = %d, want 1", got)
+			}
+
+			if _, err := ac.PostDataset(ctx, core.ParamsPostDataset{DataSetID: want.DatasetId, ProcessMode: core.ProcessModeDelete}); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := srv.Dataset(want.DatasetId); ok {
+				t.Error("dataset not deleted")
+			}
+			_, err = ac.PostDataset(ctx, core.ParamsPostDataset{DataSetID: want.DatasetId, ProcessMode: core.ProcessModeDelete})
+			if !errors.Is(err, core.ErrInvalidParameter) {
+				t.Errorf("err = %v, want ErrInvalidParameter", err)
+			}
+		})
+	}
+}
+
+func TestGetStatsDataWithDataset(t *testing.T) {
+	srv := newServer(t)
+	srv.AddDataset(estattest.Dataset{
+		ID:          "test-dataset",
+		StatsDataId: testTableInf.ID,
+		Condition:   core.NarrowingConditon{CategoryCondition: core.CategoryCondition{CodeCat01: "11"}},
+	})
+
+	resp, err := http.Get(srv.URL + "/getStatsData?dataSetId=test-dataset&cdTime=2022000406")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var data core.ResponseGetStatsDataRoot
+	if err := xml.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+	if got := data.DataList.Data.Value; len(got) != 1 || got[0].Value != "543021" {
+		t.Errorf("values = %+v", got)
+	}
+}
+
+func TestGetDataCatalog(t *testing.T) {
+	srv := newServer(t)
+	for _, id := range []string{"000001", "000002", "000003"} {
+		srv.AddDataCatalog(core.DataCatalogInf{
+			ID: id,
+			Dataset: core.Dataset{
+				StatName: core.StatName{Code: "00200521", Name: "国勢調査"},
+				Title:    core.DatasetTitle{Name: "平成27年国勢調査"},
+			},
+			Resources: []core.Resources{
+				{Resource: core.Resource{ID: id + "01", Format: "CSV"}},
+				{Resource: core.Resource{ID: id + "02", Format: "XLS"}},
+			},
+		})
+	}
+
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			ac := srv.ApiClient(core.WithFormat(f.format))
+
+			catalogs, err := ac.NewDataCatalogPager(core.ParamsGetDataCatalog{SearchWord: "国勢調査", DataType: "CSV", Limit: 2}, 0).All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(catalogs) != 3 {
+				t.Fatalf("len(catalogs) = %d, want 3", len(catalogs))
+			}
+			for _, c := range catalogs {
+				if len(c.Resources) != 1 || c.Resources[0].Resource.Format != "CSV" {
+					t.Errorf("Resources = %+v", c.Resources)
+				}
+			}
+		})
+	}
+}
+
+func TestAppID(t *testing.T) {
+	srv := newServer(t, estattest.WithAppID("test-app-id"))
+
+	if _, err := srv.ApiClient().GetMetaInfoList(context.Background(), core.ParamsGetMetaInfoList{StatsDataId: testTableInf.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	ac := core.NewApiClient(srv.Client(), core.CommonParams{AppID: "wrong"})
+	_, err := ac.GetMetaInfoList(context.Background(), core.ParamsGetMetaInfoList{StatsDataId: testTableInf.ID})
+	if !errors.Is(err, core.ErrInvalidAppID) {
+		t.Errorf("err = %v, want ErrInvalidAppID", err)
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	srv := newServer(t)
+	ac := srv.ApiClient()
+	ctx := context.Background()
+	params := core.ParamsGetMetaInfoList{StatsDataId: testTableInf.ID}
+
+	srv.InjectFault("/getMetaInfo", estattest.Fault{HTTPStatus: http.StatusServiceUnavailable, Times: 1})
+	srv.InjectFault("/getMetaInfo", estattest.Fault{Status: 200, ErrorMsg: "システムエラー", Times: 1})
+
+	if _, err := ac.GetMetaInfoList(ctx, params); !errors.Is(err, core.ErrHTTPStatus) {
+		t.Errorf("err = %v, want ErrHTTPStatus", err)
+	}
+	if _, err := ac.GetMetaInfoList(ctx, params); !errors.Is(err, core.ErrServer) {
+		t.Errorf("err = %v, want ErrServer", err)
+	}
+	if _, err := ac.GetMetaInfoList(ctx, params); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+
+	srv.InjectFault("/getMetaInfo", estattest.Fault{Latency: time.Second})
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := ac.GetMetaInfoList(timeout, params); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want DeadlineExceeded", err)
+	}
+
+	srv.ClearFaults()
+	if _, err := ac.GetMetaInfoList(ctx, params); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const fileCacheExt = ".json"
+
+// ディレクトリにエントリを 1 件 1 ファイルで保存するキャッシュ
+//
+// 同じディレクトリを複数のプロセスで共有できます。
+type FileCache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+}
+
+// dir にファイルの合計が最大 maxBytes バイトまで保存する FileCache を返します。
+//
+// maxBytes が 0 以下の場合は制限しません。上限を超えると最も長く使われていないエントリから削除します。
+func NewFileCache(dir string, maxBytes int64) *FileCache {
+	return &FileCache{dir: dir, maxBytes: maxBytes}
+}
+
+// ファイルの内容
+type fileCacheRecord struct {
+	Key   string
+	Entry CacheEntry
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+fileCacheExt)
+}
+
+func (c *FileCache) Get(key string) (CacheEntry, bool, error) {
+	path := c.path(key)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return CacheEntry{}, false, nil
+	}
+	if err != nil {
+		return CacheEntry{}, false, err
+	}
+
+	var record fileCacheRecord
+	if err := json.Unmarshal(b, &record); err != nil {
+		return CacheEntry{}, false, err
+	}
+	if record.Key != key {
+		return CacheEntry{}, false, nil
+	}
+
+	// 最終使用日時として更新時刻を使う
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return record.Entry, true, nil
+}
+
+func (c *FileCache) Set(key string, entry CacheEntry) error {
+	b, err := json.Marshal(fileCacheRecord{Key: key, Entry: entry})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	// 書き込み途中のファイルを読まないよう、一時ファイルを経由する
+	f, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if c.maxBytes > 0 {
+		return c.evict()
+	}
+	return nil
+}
+
+func (c *FileCache) Delete(key string) error {
+	err := os.Remove(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (c *FileCache) Range(f func(key string, entry CacheEntry) bool) error {
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileCacheExt) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(c.dir, e.Name()))
+		if err != nil {
+			// 他のプロセスが削除した
+			continue
+		}
+		var record fileCacheRecord
+		if err := json.Unmarshal(b, &record); err != nil {
+			continue
+		}
+		if !f(record.Key, record.Entry) {
+			break
+		}
+	}
+	return nil
+}
+
+// 合計が maxBytes を超えている間、更新時刻の古いファイルから削除する。
+func (c *FileCache) evict() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	var files []fs.FileInfo
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileCacheExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// 他のプロセスが削除した
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, info := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		total -= info.Size()
+	}
+	return nil
+}
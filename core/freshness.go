@@ -0,0 +1,278 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// e-Stat の日付のタイムゾーン
+var jst = time.FixedZone("JST", 9*60*60)
+
+// 統計表 ID ごとにキャッシュする API
+var freshnessEndpoints = map[string]bool{
+	"/getMetaInfo":        true,
+	"/getStatsData":       true,
+	"/getSimpleStatsData": true,
+}
+
+// キャッシュした統計表の更新を確認し、更新された統計表のキャッシュだけを削除します。
+//
+// 統計表情報取得 (UpdatedDate で前回の確認以降に更新された統計表を検索) を使うため、
+// TTL で期限を切るよりも少ないリクエストで新しいデータを取得できます。
+//
+//	cc := core.NewCachingClient(core.New(), core.NewFileCache(dir, 0))
+//	ac := core.NewApiClient(cc, core.CommonParams{AppID: appID})
+//	w := core.NewFreshnessWatcher(ac, cc, core.ParamsGetStatsList{})
+//	w.OnTableUpdated(func(statsDataId string) { ... })
+//	go w.Run(ctx, time.Hour, nil)
+type FreshnessWatcher struct {
+	api    IApiClient
+	cache  *CachingClient
+	params ParamsGetStatsList
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	onUpdated []func(statsDataId string)
+
+	// 統計表 ID ごとに、検索で見つけた更新と最初に見つけた確認の開始時刻
+	seen map[string]tableUpdate
+}
+
+// 統計表情報取得で見つけた更新
+type tableUpdate struct {
+	// UPDATED_DATE (yyyymmdd)
+	date string
+
+	// 最初に見つけた確認の開始時刻。更新はこれより前に行われている
+	seenAt time.Time
+}
+
+// api で統計表の更新を確認し、cache のエントリを削除する FreshnessWatcher を返します。
+//
+// cache の保存先は CacheRanger を実装している必要があります。
+// params には StatsCode などの検索条件を指定できます。UpdatedDate は上書きされます。
+func NewFreshnessWatcher(api IApiClient, cache *CachingClient, params ParamsGetStatsList) *FreshnessWatcher {
+	return &FreshnessWatcher{api: api, cache: cache, params: params}
+}
+
+// 統計表が更新され、キャッシュを削除したときに呼ぶ関数を登録します。
+func (w *FreshnessWatcher) OnTableUpdated(f func(statsDataId string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.onUpdated = append(w.onUpdated, f)
+}
+
+// キャッシュした統計表
+type cachedTable struct {
+	// キャッシュした内容の UPDATED_DATE (yyyymmdd)。複数ある場合は最も古いもの
+	updatedDate string
+
+	// 保存した時刻。複数ある場合は最も古いもの
+	storedAt time.Time
+
+	keys []string
+}
+
+// キャッシュした統計表の更新を確認し、更新された統計表 ID を返します。
+func (w *FreshnessWatcher) Check(ctx context.Context) ([]string, error) {
+	ranger, ok := w.cache.backend.(CacheRanger)
+	if !ok {
+		return nil, fmt.Errorf("e-stat: cache backend %T does not implement CacheRanger", w.cache.backend)
+	}
+
+	start := time.Now()
+	tables, err := cachedTables(ranger)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		w.setLastCheck(start)
+		return nil, nil
+	}
+
+	// 前回の確認以降 (初回はキャッシュした最も古い更新日以降) に更新された統計表を検索する
+	since := w.getLastCheck()
+	from := since.In(jst).Format("20060102")
+	if since.IsZero() {
+		from = ""
+		for _, t := range tables {
+			if from == "" || t.updatedDate < from {
+				from = t.updatedDate
+			}
+		}
+	}
+	params := w.params
+	params.UpdatedDate = from + "-" + start.In(jst).Format("20060102")
+
+	updated, err := w.api.NewStatsListPager(params, 0).All(WithCacheMode(ctx, CacheBypass))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := w.recordUpdates(updated, start)
+
+	var ids []string
+	for _, inf := range updated {
+		t, ok := tables[inf.ID]
+		if !ok || !t.staleFor(seen[inf.ID]) {
+			continue
+		}
+		for _, key := range t.keys {
+			if err := w.cache.backend.Delete(key); err != nil {
+				return ids, err
+			}
+		}
+		delete(tables, inf.ID)
+		ids = append(ids, inf.ID)
+	}
+	w.setLastCheck(start)
+
+	w.mu.Lock()
+	hooks := append([]func(string){}, w.onUpdated...)
+	w.mu.Unlock()
+	for _, id := range ids {
+		for _, f := range hooks {
+			f(id)
+		}
+	}
+
+	return ids, nil
+}
+
+// interval ごとに Check を呼びます。ctx が終了するまで戻りません。
+//
+// onError が nil でない場合、Check のエラーを渡します。
+func (w *FreshnessWatcher) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// 検索で見つけた更新を記録し、統計表 ID ごとに返す。
+//
+// 前回の確認でも見つけた同じ日付の更新は、最初に見つけた時刻を引き継ぐ。
+func (w *FreshnessWatcher) recordUpdates(updated []TableInf, now time.Time) map[string]tableUpdate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := make(map[string]tableUpdate, len(updated))
+	for _, inf := range updated {
+		u := tableUpdate{date: normalizeDate(inf.UpdatedDate), seenAt: now}
+		if prev, ok := w.seen[inf.ID]; ok && prev.date == u.date {
+			u = prev
+		}
+		seen[inf.ID] = u
+	}
+	w.seen = seen
+	return seen
+}
+
+// キャッシュした内容が u の更新より古いかを返す。
+//
+// UPDATED_DATE は日付だけのため、キャッシュした内容と同じ日の更新は、その日に保存したエントリのうち
+// 更新を最初に見つけた確認より前に保存したものを古いとみなす。
+func (t *cachedTable) staleFor(u tableUpdate) bool {
+	switch {
+	case u.date > t.updatedDate:
+		return true
+	case u.date < t.updatedDate:
+		return false
+	case t.storedAt.In(jst).Format("20060102") > u.date:
+		// 更新の翌日以降に保存した
+		return false
+	}
+	return t.storedAt.Before(u.seenAt)
+}
+
+func (w *FreshnessWatcher) getLastCheck() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.lastCheck
+}
+
+func (w *FreshnessWatcher) setLastCheck(t time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastCheck = t
+}
+
+// 統計表 ID ごとにキャッシュのエントリを集める。
+func cachedTables(ranger CacheRanger) (map[string]*cachedTable, error) {
+	tables := map[string]*cachedTable{}
+	err := ranger.Range(func(key string, entry CacheEntry) bool {
+		if !freshnessEndpoints[endpointOf(entry.Path)] {
+			return true
+		}
+		query, err := url.ParseQuery(entry.Query)
+		if err != nil {
+			return true
+		}
+		id := query.Get("statsDataId")
+		if id == "" {
+			return true
+		}
+
+		updatedDate := tableUpdatedDate(entry.Body)
+		if updatedDate == "" {
+			updatedDate = entry.StoredAt.In(jst).Format("20060102")
+		}
+
+		t, ok := tables[id]
+		if !ok {
+			t = &cachedTable{updatedDate: updatedDate, storedAt: entry.StoredAt}
+			tables[id] = t
+		}
+		if updatedDate < t.updatedDate {
+			t.updatedDate = updatedDate
+		}
+		if entry.StoredAt.Before(t.storedAt) {
+			t.storedAt = entry.StoredAt
+		}
+		t.keys = append(t.keys, key)
+		return true
+	})
+	return tables, err
+}
+
+var updatedDatePattern = regexp.MustCompile(`<UPDATED_DATE>([0-9-]+)</UPDATED_DATE>|"UPDATED_DATE"\s*:\s*"([0-9-]+)"`)
+
+// レスポンスの TABLE_INF の UPDATED_DATE を yyyymmdd で返す。
+func tableUpdatedDate(body []byte) string {
+	i := bytes.Index(body, []byte("TABLE_INF"))
+	if i < 0 {
+		return ""
+	}
+	m := updatedDatePattern.FindSubmatch(body[i:])
+	if m == nil {
+		return ""
+	}
+	if m[1] != nil {
+		return normalizeDate(string(m[1]))
+	}
+	return normalizeDate(string(m[2]))
+}
+
+// "2022-06-24" を "20220624" にする。
+func normalizeDate(date string) string {
+	return strings.ReplaceAll(date, "-", "")
+}
@@ -0,0 +1,160 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+// 統計表情報取得で updated の UPDATED_DATE を返す IHttpClient
+type freshnessHttpClient struct {
+	*countingHttpClient
+
+	mu           sync.Mutex
+	updated      map[string]string
+	updatedDates []string
+}
+
+func (hc *freshnessHttpClient) Get(ctx context.Context, path string, query any) (int, []byte, error) {
+	if path != "/getStatsList" {
+		return hc.countingHttpClient.Get(ctx, path, query)
+	}
+
+	params := query.(core.ParamsGetStatsListRoot)
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.updatedDates = append(hc.updatedDates, params.UpdatedDate)
+
+	if len(hc.updated) == 0 {
+		return http.StatusOK, []byte(`<GET_STATS_LIST><RESULT><STATUS>1</STATUS></RESULT></GET_STATS_LIST>`), nil
+	}
+	var tables string
+	for id, date := range hc.updated {
+		tables += fmt.Sprintf(`<TABLE_INF id="%s"><UPDATED_DATE>%s</UPDATED_DATE></TABLE_INF>`, id, date)
+	}
+	return http.StatusOK, []byte(fmt.Sprintf(`<GET_STATS_LIST><RESULT><STATUS>0</STATUS></RESULT><DATALIST_INF><NUMBER>%d</NUMBER><RESULT_INF><FROM_NUMBER>1</FROM_NUMBER><TO_NUMBER>%d</TO_NUMBER></RESULT_INF>%s</DATALIST_INF></GET_STATS_LIST>`, len(hc.updated), len(hc.updated), tables)), nil
+}
+
+func TestFreshnessWatcher(t *testing.T) {
+	ctx := context.Background()
+	hc := &freshnessHttpClient{countingHttpClient: newCountingHttpClient()}
+	cc := core.NewCachingClient(hc, core.NewMemoryCache(0, 0))
+	ac := core.NewApiClient(cc, core.CommonParams{})
+
+	w := core.NewFreshnessWatcher(ac, cc, core.ParamsGetStatsList{StatsCode: 200524})
+	var notified []string
+	w.OnTableUpdated(func(statsDataId string) {
+		notified = append(notified, statsDataId)
+	})
+
+	// キャッシュがない場合は問い合わせない
+	empty := core.NewFreshnessWatcher(ac, cc, core.ParamsGetStatsList{})
+	if ids, err := empty.Check(ctx); err != nil || ids != nil {
+		t.Fatalf("Check() = %v, %v", ids, err)
+	}
+	if len(hc.updatedDates) != 0 {
+		t.Errorf("getStatsList requests = %d, want 0", len(hc.updatedDates))
+	}
+
+	// UPDATED_DATE が 2022-09-16 の統計表をキャッシュする
+	params := core.ParamsGetMetaInfoList{StatsDataId: "0003109741"}
+	if _, err := ac.GetMetaInfoList(ctx, params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ac.GetStatsData(ctx, core.ParamsGetStatsData{StatsDataId: "0003109741"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 同じ日付の場合は更新されていない
+	hc.updated = map[string]string{"0003109741": "2022-09-16", "0000000001": "2022-10-01"}
+	ids, err := w.Check(ctx)
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("Check() = %v, %v", ids, err)
+	}
+	if got := hc.updatedDates[0]; got[:9] != "20220916-" {
+		t.Errorf("updatedDate = %q, want 20220916-", got)
+	}
+
+	if _, err := ac.GetMetaInfoList(ctx, params); err != nil {
+		t.Fatal(err)
+	}
+	if got := hc.count("/getMetaInfo"); got != 1 {
+		t.Errorf("getMetaInfo requests = %d, want 1", got)
+	}
+
+	// 2 回目以降は前回の確認以降を検索する
+	hc.updated = map[string]string{"0003109741": "2022-10-01"}
+	ids, err = w.Check(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"0003109741"}; !reflect.DeepEqual(ids, want) || !reflect.DeepEqual(notified, want) {
+		t.Errorf("Check() = %v, notified %v, want %v", ids, notified, want)
+	}
+
+	// 更新された統計表は取得し直す
+	if _, err := ac.GetMetaInfoList(ctx, params); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ac.GetStatsData(ctx, core.ParamsGetStatsData{StatsDataId: "0003109741"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := hc.count("/getMetaInfo"); got != 2 {
+		t.Errorf("getMetaInfo requests = %d, want 2", got)
+	}
+	if got := hc.count("/getStatsData"); got != 2 {
+		t.Errorf("getStatsData requests = %d, want 2", got)
+	}
+
+	// 統計表情報取得はキャッシュしない
+	if got := cc.Stats().Stores; got != 4 {
+		t.Errorf("Stores = %d, want 4", got)
+	}
+}
+
+func TestFreshnessWatcherSameDay(t *testing.T) {
+	ctx := context.Background()
+	hc := &freshnessHttpClient{countingHttpClient: newCountingHttpClient()}
+	backend := core.NewMemoryCache(0, 0)
+	cc := core.NewCachingClient(hc, backend)
+	w := core.NewFreshnessWatcher(core.NewApiClient(cc, core.CommonParams{}), cc, core.ParamsGetStatsList{})
+
+	// 今日更新された統計表を今日キャッシュした
+	today := time.Now().In(time.FixedZone("JST", 9*60*60)).Format("2006-01-02")
+	store := func() {
+		backend.Set(core.CacheKey("/getMetaInfo", "statsDataId=0003109741", ""), core.CacheEntry{
+			Path:     "/getMetaInfo",
+			Query:    "statsDataId=0003109741",
+			Body:     []byte(fmt.Sprintf(`<GET_META_INFO><RESULT><STATUS>0</STATUS></RESULT><METADATA_INF><TABLE_INF id="0003109741"><UPDATED_DATE>%s</UPDATED_DATE></TABLE_INF></METADATA_INF></GET_META_INFO>`, today)),
+			StoredAt: time.Now(),
+		})
+	}
+	store()
+	hc.updated = map[string]string{"0003109741": today}
+
+	// 日付が同じでも、キャッシュの後に更新された可能性がある
+	ids, err := w.Check(ctx)
+	if err != nil || !reflect.DeepEqual(ids, []string{"0003109741"}) {
+		t.Fatalf("Check() = %v, %v, want [0003109741]", ids, err)
+	}
+
+	// 更新を見つけた後に取得し直したキャッシュは新しい
+	store()
+	if ids, err := w.Check(ctx); err != nil || len(ids) != 0 {
+		t.Errorf("Check() = %v, %v, want none", ids, err)
+	}
+}
+
+func TestFreshnessWatcherBackend(t *testing.T) {
+	cc := core.NewCachingClient(newCountingHttpClient(), struct{ core.CacheBackend }{core.NewMemoryCache(0, 0)})
+	w := core.NewFreshnessWatcher(core.NewApiClient(cc, core.CommonParams{}), cc, core.ParamsGetStatsList{})
+	if _, err := w.Check(context.Background()); err == nil {
+		t.Error("Check() error = nil, want error for backend without Range")
+	}
+}
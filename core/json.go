@@ -0,0 +1,290 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// e-Stat の JSON 形式のレスポンスは XML 形式のレスポンスを機械的に変換したもので、
+// 以下のような特徴がある。
+//
+// ・属性は "@" を前置したキー、要素のテキストは "$" をキーとして出力される
+//
+// ・属性を持たない要素はテキストのみが文字列として出力される
+//
+// ・繰り返し要素が 1 件の場合は配列ではなくオブジェクトとして出力される
+//
+// ・空要素は空文字列として、数値に見えるテキストは数値として出力される
+//
+// unmarshalJSON はこれらを考慮し、v の xml タグを元に JSON を読み込む。
+func unmarshalJSON(b []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var src any
+	if err := dec.Decode(&src); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	return decodeJSONValue(src, rv.Elem())
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func decodeJSONValue(src any, dst reflect.Value) error {
+	if src == nil {
+		return nil
+	}
+
+	if dst.Type() == timeType {
+		s, err := jsonText(src, dst.Type())
+		if err != nil || s == "" {
+			return err
+		}
+		return dst.Addr().Interface().(*time.Time).UnmarshalText([]byte(s))
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeJSONValue(src, dst.Elem())
+
+	case reflect.Struct:
+		fields := jsonFieldsOf(dst.Type())
+		obj, ok := src.(map[string]any)
+		if !ok {
+			// 属性を持たない要素はテキストのみが出力される
+			if text, ok := fields["$"]; ok {
+				return decodeJSONValue(src, dst.FieldByIndex(text))
+			}
+			if s, ok := src.(string); ok && s == "" {
+				return nil
+			}
+			return &json.UnmarshalTypeError{Value: fmt.Sprintf("%T", src), Type: dst.Type()}
+		}
+		for key, value := range obj {
+			index, ok := fields[key]
+			if !ok {
+				continue
+			}
+			if err := decodeJSONValue(value, dst.FieldByIndex(index)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		items, ok := src.([]any)
+		if !ok {
+			// 繰り返し要素が 1 件の場合は配列で出力されない
+			items = []any{src}
+		}
+		s := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeJSONValue(item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+
+	case reflect.String:
+		s, err := jsonText(src, dst.Type())
+		if err != nil {
+			return err
+		}
+		dst.SetString(s)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s, err := jsonText(src, dst.Type())
+		if err != nil || s == "" {
+			return err
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, dst.Type().Bits())
+		if err != nil {
+			return &json.UnmarshalTypeError{Value: "number " + s, Type: dst.Type()}
+		}
+		dst.SetInt(n)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		s, err := jsonText(src, dst.Type())
+		if err != nil || s == "" {
+			return err
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), dst.Type().Bits())
+		if err != nil {
+			return &json.UnmarshalTypeError{Value: "number " + s, Type: dst.Type()}
+		}
+		dst.SetFloat(n)
+		return nil
+	}
+
+	return &json.UnmarshalTypeError{Value: fmt.Sprintf("%T", src), Type: dst.Type()}
+}
+
+// jsonText は文字列・数値・真偽値、またはテキストを持つオブジェクトを文字列として取り出す。
+func jsonText(src any, t reflect.Type) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case map[string]any:
+		if text, ok := v["$"]; ok {
+			return jsonText(text, t)
+		}
+	}
+	return "", &json.UnmarshalTypeError{Value: fmt.Sprintf("%T", src), Type: t}
+}
+
+var jsonFieldsCache sync.Map
+
+// jsonFieldsOf は構造体の xml タグから JSON のキーとフィールドの対応を作る。
+func jsonFieldsOf(t reflect.Type) map[string][]int {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string][]int)
+	}
+
+	fields := map[string][]int{}
+	collectJSONFields(t, nil, fields)
+
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+func collectJSONFields(t reflect.Type, parent []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		tag := f.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			collectJSONFields(f.Type, index, fields)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		key := name
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "attr":
+				key = "@" + name
+			case "innerxml", "chardata":
+				key = "$"
+			}
+		}
+
+		// 埋め込みよりも浅い階層のフィールドを優先する
+		if _, ok := fields[key]; ok && len(parent) > 0 {
+			continue
+		}
+		fields[key] = index
+	}
+}
+
+// 属性とテキストを持つ要素
+
+func (v *StatName) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *GovOrg) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *MainCategory) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *SubCategory) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *Organization) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *Title) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ClassObjExplanation) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ClassObjClass) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *DataInfNote) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *DataInfAnnotation) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *DataInfValue) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+// レスポンスのルート要素
+
+func (v *ResponseGetStatsListRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ResponseGetMetaInfoListRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ResponseGetStatsDataRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ResponseGetStatsDatasRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ResponsePostDatasetRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ResponseRefDatasetRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ResponseGetDatasetListRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
+
+func (v *ResponseGetDataCatalogRoot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, v)
+}
@@ -0,0 +1,190 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ログで appId などの値の代わりに出力する文字列
+const redacted = "REDACTED"
+
+// ログやエラーに値を出力しないパラメータ
+var secretParams = []string{"appId"}
+
+// RESULT の STATUS を探すレスポンスの先頭のバイト数
+const resultHeadSize = 1024
+
+// リクエストのログを出力する *slog.Logger を指定し、ログの出力を有効にします。
+//
+// リクエストごとに以下の属性を持つレコードを出力します。appId は常に伏せられます。
+//
+// ・method、endpoint、query：リクエスト (query は appId を伏せたもの)
+//
+// ・status、bytes、duration：HTTP のステータスコード、レスポンスのバイト数、所要時間
+//
+// ・result_status：レスポンスの RESULT の STATUS (ある場合のみ)
+//
+// ・retries：リトライした回数
+//
+// 通常は slog.LevelInfo、HTTP のステータスコードや RESULT の STATUS がエラーの場合は slog.LevelWarn、
+// 通信に失敗した場合は slog.LevelError で出力します。
+func WithSlogLogger(logger *slog.Logger) Option {
+	return func(c *HttpClient) {
+		c.logger = logger
+	}
+}
+
+// 出力するログの最低レベルを指定します。省略値は slog.LevelInfo です。
+//
+// slog.LevelDebug を指定するとリトライの待機もログに出力します。
+func WithLogLevel(level slog.Level) Option {
+	return func(c *HttpClient) {
+		c.logLevel = level
+	}
+}
+
+// Logger に 1 行ずつ書き込む io.Writer
+type printfWriter struct {
+	logger Logger
+}
+
+func (w printfWriter) Write(p []byte) (int, error) {
+	w.logger.Printf("%s", strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// Logger に slog のテキスト形式で出力する *slog.Logger を返す。
+func printfLogger(logger Logger) *slog.Logger {
+	return slog.New(slog.NewTextHandler(printfWriter{logger: logger}, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func (c *HttpClient) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger == nil || level < c.logLevel || !c.logger.Enabled(ctx, level) {
+		return
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// 1 回のリクエスト (リトライを含む) のログを出力する。
+func (c *HttpClient) logRequest(req *http.Request, endpoint string, start time.Time, retries int, statusCode int, n int64, head []byte, err error) {
+	level := slog.LevelInfo
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", endpoint),
+		slog.String("query", redactQuery(requestQuery(req))),
+	}
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", statusCode), slog.Int64("bytes", n))
+		if !isSuccessHTTPStatus(statusCode) {
+			level = slog.LevelWarn
+		}
+		if status, ok := resultStatusOf(head); ok {
+			attrs = append(attrs, slog.Int("result_status", status))
+			if status >= resultStatusError {
+				level = slog.LevelWarn
+			}
+		}
+	}
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)), slog.Int("retries", retries))
+
+	c.log(req.Context(), level, "e-stat request", attrs...)
+}
+
+// リトライの待機のログを出力する。
+func (c *HttpClient) logRetry(req *http.Request, endpoint string, attempt int, delay time.Duration, resp *http.Response, err error) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", endpoint),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+
+	c.log(req.Context(), slog.LevelDebug, "e-stat retry", attrs...)
+}
+
+// ログに出力するクエリ。フォームで送信する POST はボディを使う。
+func requestQuery(req *http.Request) string {
+	if req.Method == http.MethodPost && req.Header.Get("Content-Type") == "application/x-www-form-urlencoded" && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(body)
+			return string(b)
+		}
+	}
+	return req.URL.RawQuery
+}
+
+// appId などの値を伏せたクエリを返す。
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	for _, param := range secretParams {
+		if _, ok := query[param]; ok {
+			query.Set(param, redacted)
+		}
+	}
+	return query.Encode()
+}
+
+// *url.Error の URL から appId などの値を伏せる。
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+		u.RawQuery = redactQuery(u.RawQuery)
+		urlErr.URL = u.String()
+	} else {
+		urlErr.URL = redacted
+	}
+	return err
+}
+
+// 読み込んだバイト数を数え、閉じたときにリクエストのログを出力する io.ReadCloser
+type loggingBody struct {
+	io.ReadCloser
+
+	// 読み込んだバイト数と、RESULT の STATUS を探す先頭部分
+	n    int64
+	head []byte
+
+	once sync.Once
+	done func(n int64, head []byte)
+}
+
+func (b *loggingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if rest := resultHeadSize - len(b.head); rest > 0 {
+		b.head = append(b.head, p[:min(n, rest)]...)
+	}
+	return n, err
+}
+
+func (b *loggingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.done(b.n, b.head)
+	})
+	return err
+}
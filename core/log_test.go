@@ -0,0 +1,267 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+const secretAppID = "secret-app-id"
+
+// JSON 形式で出力したログのレコードを読み込む。
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	if strings.Contains(buf.String(), secretAppID) {
+		t.Errorf("log contains appId: %s", buf.String())
+	}
+
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var r map[string]any
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func newJSONLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestSlogLogger(t *testing.T) {
+	body := []byte(`<GET_STATS_LIST><RESULT><STATUS>0</STATUS></RESULT></GET_STATS_LIST>`)
+	errorBody := []byte(`<GET_STATS_LIST><RESULT><STATUS>100</STATUS></RESULT></GET_STATS_LIST>`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("searchWord") == "error" {
+			w.Write(errorBody)
+			return
+		}
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		do   func(ac core.IApiClient) error
+		want map[string]any
+	}{
+		{
+			name: "Get",
+			do: func(ac core.IApiClient) error {
+				_, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{SearchWord: "人口"})
+				return err
+			},
+			want: map[string]any{
+				"level":         "INFO",
+				"msg":           "e-stat request",
+				"method":        "GET",
+				"endpoint":      "/getStatsList",
+				"query":         "appId=REDACTED&searchWord=%E4%BA%BA%E5%8F%A3",
+				"status":        float64(200),
+				"bytes":         float64(len(body)),
+				"result_status": float64(0),
+				"retries":       float64(0),
+			},
+		},
+		{
+			name: "Error status",
+			do: func(ac core.IApiClient) error {
+				ac.GetStatsList(context.Background(), core.ParamsGetStatsList{SearchWord: "error"})
+				return nil
+			},
+			want: map[string]any{
+				"level":         "WARN",
+				"msg":           "e-stat request",
+				"method":        "GET",
+				"endpoint":      "/getStatsList",
+				"query":         "appId=REDACTED&searchWord=error",
+				"status":        float64(200),
+				"bytes":         float64(len(errorBody)),
+				"result_status": float64(100),
+				"retries":       float64(0),
+			},
+		},
+		{
+			name: "Post form",
+			do: func(ac core.IApiClient) error {
+				ac.PostDataset(context.Background(), core.ParamsPostDataset{StatsDataId: "0003109741"})
+				return nil
+			},
+			want: map[string]any{
+				"level":         "INFO",
+				"msg":           "e-stat request",
+				"method":        "POST",
+				"endpoint":      "/postDataset",
+				"query":         "appId=REDACTED&statsDataId=0003109741",
+				"status":        float64(200),
+				"bytes":         float64(len(body)),
+				"result_status": float64(0),
+				"retries":       float64(0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			hc := core.New(core.WithBaseURL(srv.URL), core.WithSlogLogger(newJSONLogger(&buf)))
+			ac := core.NewApiClient(hc, core.CommonParams{AppID: secretAppID})
+
+			if err := tt.do(ac); err != nil {
+				t.Fatal(err)
+			}
+
+			records := logRecords(t, &buf)
+			if len(records) != 1 {
+				t.Fatalf("len(records) = %d, want 1", len(records))
+			}
+			got := records[0]
+			if _, ok := got["duration"]; !ok {
+				t.Error("duration is missing")
+			}
+			delete(got, "time")
+			delete(got, "duration")
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("record = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogLoggerRetry(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(responseGetStatsList)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		level slog.Level
+		want  []string
+	}{
+		{name: "Info", level: slog.LevelInfo, want: []string{"e-stat request"}},
+		{name: "Debug", level: slog.LevelDebug, want: []string{"e-stat retry", "e-stat request"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			var buf bytes.Buffer
+			hc := core.New(
+				core.WithBaseURL(srv.URL),
+				core.WithRetryPolicy(core.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+				core.WithSlogLogger(newJSONLogger(&buf)),
+				core.WithLogLevel(tt.level))
+			ac := core.NewApiClient(hc, core.CommonParams{AppID: secretAppID})
+
+			if _, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{}); err != nil {
+				t.Fatal(err)
+			}
+
+			records := logRecords(t, &buf)
+			var msgs []string
+			for _, r := range records {
+				msgs = append(msgs, r["msg"].(string))
+			}
+			if strings.Join(msgs, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("msgs = %v, want %v", msgs, tt.want)
+			}
+			if got := records[len(records)-1]["retries"]; got != float64(1) {
+				t.Errorf("retries = %v, want 1", got)
+			}
+		})
+	}
+}
+
+func TestSlogLoggerTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	var buf bytes.Buffer
+	hc := core.New(core.WithBaseURL(srv.URL), core.WithSlogLogger(newJSONLogger(&buf)))
+	ac := core.NewApiClient(hc, core.CommonParams{AppID: secretAppID})
+
+	_, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{})
+	if err == nil {
+		t.Fatal("GetStatsList() error = nil")
+	}
+	if strings.Contains(err.Error(), secretAppID) {
+		t.Errorf("error contains appId: %v", err)
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 || records[0]["level"] != "ERROR" || records[0]["error"] == nil {
+		t.Errorf("records = %v", records)
+	}
+}
+
+func TestCacheLogger(t *testing.T) {
+	var buf bytes.Buffer
+	hc := core.NewCachingClient(newCountingHttpClient(), core.NewMemoryCache(0, 0), core.WithCacheLogger(newJSONLogger(&buf)))
+	ac := core.NewApiClient(hc, core.CommonParams{AppID: secretAppID})
+
+	for i := 0; i < 2; i++ {
+		if _, err := ac.GetMetaInfoList(context.Background(), core.ParamsGetMetaInfoList{StatsDataId: "0003109741"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("len(records) = %d, want 1", len(records))
+	}
+	want := map[string]any{
+		"method":        "GET",
+		"endpoint":      "/getMetaInfo",
+		"query":         "statsDataId=0003109741",
+		"result_status": float64(0),
+		"cache":         "hit",
+	}
+	for k, v := range want {
+		if records[0][k] != v {
+			t.Errorf("%s = %v, want %v", k, records[0][k], v)
+		}
+	}
+}
+
+func TestClientLoggerRedactsAppID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(responseGetStatsList)
+	}))
+	defer srv.Close()
+
+	var logger bufferLogger
+	hc := core.New(core.WithBaseURL(srv.URL), core.WithLogger(&logger))
+	ac := core.NewApiClient(hc, core.CommonParams{AppID: secretAppID})
+
+	if _, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{}); err != nil {
+		t.Fatalf("GetStatsList() error = %v", err)
+	}
+	if strings.Contains(logger.String(), secretAppID) {
+		t.Errorf("log contains appId: %s", logger.String())
+	}
+}
@@ -1,13 +1,17 @@
 package core
 
-import (
-	"context"
-	"encoding/xml"
-)
+import "context"
 
 type ParamsGetMetaInfoList struct {
 	StatsDataId       string `url:"statsDataId" xml:"STATS_DATA_ID,omitempty"`
-	ExplanationGetFlg string `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG,omitempty"`
+	ExplanationGetFlg Flag   `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG,omitempty"`
+}
+
+// パラメータを検証します。
+func (p ParamsGetMetaInfoList) Validate() error {
+	var v paramValidator
+	v.flag("explanationGetFlg", p.ExplanationGetFlg)
+	return v.err()
 }
 
 type ParamsGetMetaInfoListRoot struct {
@@ -40,7 +44,10 @@ type ResponseGetMetaInfoListRoot struct {
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_2
 func (c *ApiClient) GetMetaInfoList(ctx context.Context, params ParamsGetMetaInfoList) (*ResponseGetMetaInfoListRoot, error) {
-	_, body, err := c.HttpClient.Get(ctx, "/getMetaInfo", ParamsGetMetaInfoListRoot{
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.Get(ctx, c.path("/getMetaInfo"), ParamsGetMetaInfoListRoot{
 		CommonParams:          c.CommonParams,
 		ParamsGetMetaInfoList: params,
 	})
@@ -49,9 +56,9 @@ func (c *ApiClient) GetMetaInfoList(ctx context.Context, params ParamsGetMetaInf
 	}
 
 	var data *ResponseGetMetaInfoListRoot
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/getMetaInfo", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/getMetaInfo", statusCode, data.Result)
 }
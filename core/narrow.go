@@ -0,0 +1,297 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 絞り込み条件を設定した分類事項の ID を返す。
+func (c *NarrowingConditon) conditionIDs() []string {
+	var ids []string
+	for _, id := range dimensionIDs {
+		f, _ := c.fields(id)
+		if *f.level != "" || *f.code != "" || *f.from != "" || *f.to != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// 絞り込み条件のエラー
+type NarrowError struct {
+	// 分類事項の ID と名前
+	ClassObjID   string
+	ClassObjName string
+
+	// パラメータ名 (lvArea、cdCat01From など) と値
+	Param string
+	Value string
+
+	Reason string
+}
+
+func (e *NarrowError) Error() string {
+	obj := e.ClassObjID
+	if e.ClassObjName != "" {
+		obj = fmt.Sprintf("%s (%s)", e.ClassObjName, e.ClassObjID)
+	}
+	if e.Param == "" {
+		return fmt.Sprintf("e-stat: %s: %s", obj, e.Reason)
+	}
+	return fmt.Sprintf("e-stat: %s=%s: %s in %s", e.Param, e.Value, e.Reason, obj)
+}
+
+// 複数の絞り込み条件のエラー
+type NarrowErrors []*NarrowError
+
+func (e NarrowErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// パラメータ名の分類事項の部分 ("Tab"、"Cat01" など)
+func paramName(id string) string {
+	return strings.ToUpper(id[:1]) + id[1:]
+}
+
+// 絞り込み条件のコードと階層レベルがメタ情報に含まれるかを検証します。
+//
+// 問題がある場合は NarrowErrors を返します。パラメータ自体の検証は Validate を使って下さい。
+func (c NarrowingConditon) ValidateAgainst(class ClassInf) error {
+	objs := map[string]ClassObj{}
+	for _, obj := range class.ClassObj {
+		objs[obj.ID] = obj
+	}
+
+	var errs NarrowErrors
+	for _, id := range c.conditionIDs() {
+		f, _ := c.fields(id)
+		name := paramName(id)
+
+		obj, ok := objs[id]
+		if !ok {
+			errs = append(errs, &NarrowError{ClassObjID: id, Reason: "class object not found in meta info"})
+			continue
+		}
+		newError := func(param string, value string, reason string) *NarrowError {
+			return &NarrowError{ClassObjID: id, ClassObjName: obj.Name, Param: param, Value: value, Reason: reason}
+		}
+
+		index := make(map[string]int, len(obj.Class))
+		minLevel, maxLevel := 0, 0
+		for i, c := range obj.Class {
+			if _, ok := index[c.Code]; !ok {
+				index[c.Code] = i
+			}
+			if level, err := strconv.Atoi(c.Level); err == nil {
+				if minLevel == 0 || level < minLevel {
+					minLevel = level
+				}
+				if level > maxLevel {
+					maxLevel = level
+				}
+			}
+		}
+
+		if *f.level != "" {
+			from, to, err := ParseLevel(*f.level)
+			switch {
+			case err != nil:
+				errs = append(errs, newError("lv"+name, *f.level, err.Error()))
+			case maxLevel == 0:
+				errs = append(errs, newError("lv"+name, *f.level, "class object has no levels"))
+			case (from != 0 && (from < minLevel || from > maxLevel)) || (to != 0 && (to < minLevel || to > maxLevel)):
+				errs = append(errs, newError("lv"+name, *f.level, fmt.Sprintf("level out of range %d-%d", minLevel, maxLevel)))
+			}
+		}
+
+		if *f.code != "" {
+			for _, code := range strings.Split(*f.code, ",") {
+				if _, ok := index[strings.TrimSpace(code)]; !ok {
+					errs = append(errs, newError("cd"+name, code, "code not found"))
+				}
+			}
+		}
+
+		from, fromOK := index[*f.from]
+		if *f.from != "" && !fromOK {
+			errs = append(errs, newError("cd"+name+"From", *f.from, "code not found"))
+		}
+		to, toOK := index[*f.to]
+		if *f.to != "" && !toOK {
+			errs = append(errs, newError("cd"+name+"To", *f.to, "code not found"))
+		}
+		if fromOK && toOK && from > to {
+			errs = append(errs, newError("cd"+name+"From", *f.from, "range starts after cd"+name+"To "+*f.to))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// "1"、"1-3"、"-3"、"2-" のような階層レベルの条件を解析し、範囲の下限と上限を返します。0 は指定なしを表します。
+func ParseLevel(s string) (int, int, error) {
+	fromText, toText, isRange := strings.Cut(s, "-")
+
+	parse := func(s string) (int, error) {
+		if s == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid level")
+		}
+		return n, nil
+	}
+
+	from, err := parse(fromText)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		if from == 0 {
+			return 0, 0, fmt.Errorf("invalid level")
+		}
+		return from, from, nil
+	}
+
+	to, err := parse(toText)
+	if err != nil {
+		return 0, 0, err
+	}
+	if (from == 0 && to == 0) || (to != 0 && from > to) {
+		return 0, 0, fmt.Errorf("invalid level range")
+	}
+	return from, to, nil
+}
+
+// 絞り込み条件を組み立てます。
+//
+//	cond, err := core.Narrow().
+//		Area().Level(2).
+//		Category(1).Codes("01", "02").
+//		Time().From("2015000000").
+//		Build()
+type NarrowBuilder struct {
+	cond NarrowingConditon
+	id   string
+	err  *NarrowError
+}
+
+// 絞り込み条件の組み立てを始めます。
+func Narrow() *NarrowBuilder {
+	return &NarrowBuilder{}
+}
+
+func (b *NarrowBuilder) dim(id string) *NarrowBuilder {
+	b.id = id
+	return b
+}
+
+// 表章事項を対象にします。
+func (b *NarrowBuilder) Tab() *NarrowBuilder {
+	return b.dim("tab")
+}
+
+// 時間軸事項を対象にします。
+func (b *NarrowBuilder) Time() *NarrowBuilder {
+	return b.dim("time")
+}
+
+// 地域事項を対象にします。
+func (b *NarrowBuilder) Area() *NarrowBuilder {
+	return b.dim("area")
+}
+
+// 分類事項 n (1〜15) を対象にします。
+func (b *NarrowBuilder) Category(n int) *NarrowBuilder {
+	if _, ok := b.cond.CategoryCondition.fields(n); !ok && b.err == nil {
+		b.err = &NarrowError{ClassObjID: categoryID(n), Reason: "category must be between 1 and 15"}
+	}
+	return b.dim(categoryID(n))
+}
+
+func (b *NarrowBuilder) set(f func(conditionFields)) *NarrowBuilder {
+	fields, ok := b.cond.fields(b.id)
+	if !ok {
+		if b.err == nil {
+			b.err = &NarrowError{ClassObjID: b.id, Reason: "no dimension selected"}
+		}
+		return b
+	}
+	f(fields)
+	return b
+}
+
+// 階層レベルを指定します。
+func (b *NarrowBuilder) Level(level int) *NarrowBuilder {
+	return b.set(func(f conditionFields) {
+		*f.level = strconv.Itoa(level)
+	})
+}
+
+// 階層レベルの範囲を指定します。0 は範囲の端を指定しないことを表します。
+func (b *NarrowBuilder) LevelRange(from int, to int) *NarrowBuilder {
+	return b.set(func(f conditionFields) {
+		var s string
+		if from > 0 {
+			s = strconv.Itoa(from)
+		}
+		s += "-"
+		if to > 0 {
+			s += strconv.Itoa(to)
+		}
+		*f.level = s
+	})
+}
+
+// コードを指定します。
+func (b *NarrowBuilder) Codes(codes ...string) *NarrowBuilder {
+	return b.set(func(f conditionFields) {
+		*f.code = strings.Join(codes, ",")
+	})
+}
+
+// コードの範囲の開始を指定します。
+func (b *NarrowBuilder) From(code string) *NarrowBuilder {
+	return b.set(func(f conditionFields) {
+		*f.from = code
+	})
+}
+
+// コードの範囲の終了を指定します。
+func (b *NarrowBuilder) To(code string) *NarrowBuilder {
+	return b.set(func(f conditionFields) {
+		*f.to = code
+	})
+}
+
+// 組み立てた絞り込み条件を返します。
+func (b *NarrowBuilder) Build() (NarrowingConditon, error) {
+	if b.err != nil {
+		return NarrowingConditon{}, b.err
+	}
+	return b.cond, nil
+}
+
+// 組み立てた絞り込み条件をメタ情報で検証して返します。meta が nil の場合はエラーを返します。
+func (b *NarrowBuilder) BuildFor(meta *ResponseGetMetaInfoList) (NarrowingConditon, error) {
+	cond, err := b.Build()
+	if err != nil {
+		return NarrowingConditon{}, err
+	}
+	if meta == nil {
+		return NarrowingConditon{}, fmt.Errorf("e-stat: no meta info to validate against")
+	}
+	if err := cond.ValidateAgainst(meta.DataList.Class); err != nil {
+		return NarrowingConditon{}, err
+	}
+	return cond, nil
+}
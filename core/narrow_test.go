@@ -0,0 +1,148 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func TestNarrow(t *testing.T) {
+	cond, err := core.Narrow().
+		Area().Level(2).
+		Category(1).Codes("01", "02").
+		Time().From("2015000000").
+		Tab().LevelRange(0, 3).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := core.NarrowingConditon{
+		TabulatCondition:  core.TabulatCondition{LevelTab: "-3"},
+		TimeCondition:     core.TimeCondition{CodeTimeFrom: "2015000000"},
+		AreaCondition:     core.AreaCondition{LevelArea: "2"},
+		CategoryCondition: core.CategoryCondition{CodeCat01: "01,02"},
+	}
+	if !reflect.DeepEqual(cond, want) {
+		t.Errorf("Build() = %+v, want %+v", cond, want)
+	}
+
+	if _, err := core.Narrow().Level(1).Build(); err == nil {
+		t.Errorf("Build() without dimension error = nil")
+	}
+	if _, err := core.Narrow().Category(16).Codes("1").Build(); err == nil {
+		t.Errorf("Build() with Category(16) error = nil")
+	}
+}
+
+func TestNarrowBuildFor(t *testing.T) {
+	ctx := context.Background()
+	hc := mockHttpClient{}
+	ac := core.NewApiClient(&hc, core.CommonParams{})
+
+	meta, err := ac.GetMetaInfoList(ctx, core.ParamsGetMetaInfoList{})
+	if err != nil {
+		t.Fatalf("GetMetaInfoList() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		builder *core.NarrowBuilder
+		params  []string
+	}{
+		{
+			name:    "Valid",
+			builder: core.Narrow().Category(1).LevelRange(1, 2).Codes("11", "12").Time().From("1994000103").To("1995000103"),
+		},
+		{
+			name:    "UnknownClassObj",
+			builder: core.Narrow().Area().Level(1),
+			params:  []string{""},
+		},
+		{
+			name:    "UnknownCode",
+			builder: core.Narrow().Category(1).Codes("11", "99"),
+			params:  []string{"cdCat01"},
+		},
+		{
+			name:    "LevelOutOfRange",
+			builder: core.Narrow().Category(1).Level(5).Tab().Level(1),
+			params:  []string{"lvTab", "lvCat01"},
+		},
+		{
+			name:    "ReversedRange",
+			builder: core.Narrow().Time().From("1995000103").To("1994000103"),
+			params:  []string{"cdTimeFrom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.BuildFor(&meta.ResponseGetMetaInfoList)
+
+			var errs core.NarrowErrors
+			if tt.params == nil {
+				if err != nil {
+					t.Fatalf("BuildFor() error = %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &errs) {
+				t.Fatalf("BuildFor() error = %v, want NarrowErrors", err)
+			}
+
+			var params []string
+			for _, e := range errs {
+				params = append(params, e.Param)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("BuildFor() error = %v, want params %v", err, tt.params)
+			}
+		})
+	}
+	if _, err := core.Narrow().Area().Level(1).BuildFor(nil); err == nil {
+		t.Errorf("BuildFor(nil) error = nil")
+	}
+}
+
+func TestNarrowErrorMessage(t *testing.T) {
+	cond := core.NarrowingConditon{
+		CategoryCondition: core.CategoryCondition{CodeCat01: "99"},
+	}
+	got := cond.ValidateAgainst(core.ClassInf{ClassObj: []core.ClassObj{{ID: "cat01", Name: "男女"}}}).Error()
+	if want := "e-stat: cdCat01=99: code not found in 男女 (cat01)"; got != want {
+		t.Errorf("Error() = %s, want %s", got, want)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level    string
+		from, to int
+		wantErr  bool
+	}{
+		{level: "2", from: 2, to: 2},
+		{level: "1-3", from: 1, to: 3},
+		{level: "-3", from: 0, to: 3},
+		{level: "2-", from: 2, to: 0},
+		{level: "", wantErr: true},
+		{level: "-", wantErr: true},
+		{level: "3-1", wantErr: true},
+		{level: "a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			from, to, err := core.ParseLevel(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.level, err, tt.wantErr)
+			}
+			if from != tt.from || to != tt.to {
+				t.Errorf("ParseLevel(%q) = %d, %d, want %d, %d", tt.level, from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// StartPosition と Limit によるページ送りの共通処理
+type pager[T any] struct {
+	// total には TOTAL_NUMBER がない場合に使う NUMBER を返す
+	fetch func(ctx context.Context, startPosition int, limit int) (items []T, total int, result ResultInf, err error)
+
+	startPosition int
+	limit         int
+	maxItems      int
+	count         int
+	total         int
+	done          bool
+}
+
+func (p *pager[T]) next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, io.EOF
+	}
+
+	startPosition := p.startPosition
+	if startPosition == 0 {
+		startPosition = 1
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, &PageError{StartPosition: startPosition, Err: err}
+	}
+
+	limit := p.limit
+	if p.maxItems > 0 {
+		if remaining := p.maxItems - p.count; limit == 0 || limit > remaining {
+			limit = remaining
+		}
+	}
+
+	items, total, result, err := p.fetch(ctx, p.startPosition, limit)
+	if errors.Is(err, ErrNoData) {
+		p.done = true
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, &PageError{StartPosition: startPosition, Err: err}
+	}
+
+	p.total = result.TotalNumber
+	if p.total == 0 {
+		p.total = total
+	}
+	if p.maxItems > 0 && p.count+len(items) > p.maxItems {
+		items = items[:p.maxItems-p.count]
+	}
+	p.count += len(items)
+
+	if result.NextKey == 0 || len(items) == 0 || (p.maxItems > 0 && p.count >= p.maxItems) {
+		p.done = true
+	} else {
+		p.startPosition = result.NextKey
+	}
+
+	return items, nil
+}
+
+func (p *pager[T]) all(ctx context.Context) ([]T, error) {
+	var all []T
+	for {
+		items, err := p.next(ctx)
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+}
+
+// 統計表情報取得の検索結果をページ送りします。
+type StatsListPager struct {
+	p pager[TableInf]
+}
+
+// 統計表情報取得の検索結果をページ送りするページャを返します。
+//
+// params の Limit を 1 ページの件数とし、maxItems が 0 より大きい場合はその件数で打ち切ります。
+func (c *ApiClient) NewStatsListPager(params ParamsGetStatsList, maxItems int) *StatsListPager {
+	return &StatsListPager{
+		p: pager[TableInf]{
+			fetch: func(ctx context.Context, startPosition int, limit int) ([]TableInf, int, ResultInf, error) {
+				params.StartPosition = startPosition
+				params.Limit = limit
+
+				data, err := c.GetStatsList(ctx, params)
+				if err != nil {
+					return nil, 0, ResultInf{}, err
+				}
+				if data.DataList == nil {
+					return nil, 0, ResultInf{}, nil
+				}
+
+				return data.DataList.Table, data.DataList.Number, data.DataList.Result, nil
+			},
+			startPosition: params.StartPosition,
+			limit:         params.Limit,
+			maxItems:      maxItems,
+		},
+	}
+}
+
+// 次のページを返します。ページがなくなると io.EOF を返します。
+func (p *StatsListPager) Next(ctx context.Context) ([]TableInf, error) {
+	return p.p.next(ctx)
+}
+
+// 残りのページをすべて取得します。エラーの場合もそれまでに取得した結果を返します。
+func (p *StatsListPager) All(ctx context.Context) ([]TableInf, error) {
+	return p.p.all(ctx)
+}
+
+// 検索結果の総件数を返します。最初のページを取得するまでは 0 です。
+func (p *StatsListPager) Total() int {
+	return p.p.total
+}
+
+// データカタログ情報取得の検索結果をページ送りします。
+type DataCatalogPager struct {
+	p pager[DataCatalogInf]
+}
+
+// データカタログ情報取得の検索結果をページ送りするページャを返します。
+//
+// params の Limit を 1 ページの件数とし、maxItems が 0 より大きい場合はその件数で打ち切ります。
+func (c *ApiClient) NewDataCatalogPager(params ParamsGetDataCatalog, maxItems int) *DataCatalogPager {
+	return &DataCatalogPager{
+		p: pager[DataCatalogInf]{
+			fetch: func(ctx context.Context, startPosition int, limit int) ([]DataCatalogInf, int, ResultInf, error) {
+				params.StartPosition = startPosition
+				params.Limit = limit
+
+				data, err := c.GetDataCatalog(ctx, params)
+				if err != nil {
+					return nil, 0, ResultInf{}, err
+				}
+
+				return data.DataCatalogList.DataCatalog, data.DataCatalogList.Number, data.DataCatalogList.Result, nil
+			},
+			startPosition: params.StartPosition,
+			limit:         params.Limit,
+			maxItems:      maxItems,
+		},
+	}
+}
+
+// 次のページを返します。ページがなくなると io.EOF を返します。
+func (p *DataCatalogPager) Next(ctx context.Context) ([]DataCatalogInf, error) {
+	return p.p.next(ctx)
+}
+
+// 残りのページをすべて取得します。エラーの場合もそれまでに取得した結果を返します。
+func (p *DataCatalogPager) All(ctx context.Context) ([]DataCatalogInf, error) {
+	return p.p.all(ctx)
+}
+
+// 検索結果の総件数を返します。最初のページを取得するまでは 0 です。
+func (p *DataCatalogPager) Total() int {
+	return p.p.total
+}
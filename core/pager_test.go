@@ -0,0 +1,136 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+)
+
+// 統計表 ID の一覧を検索結果として返す IHttpClient。do を estattest.ClientFunc として使う
+type statsListHttpClient struct {
+	ids      []string
+	requests []core.ParamsGetStatsList
+}
+
+func (hc *statsListHttpClient) do(ctx context.Context, r estattest.ClientRequest) (int, []byte, error) {
+	if r.Path != "/getStatsList" {
+		return http.StatusNotFound, nil, nil
+	}
+	params := r.Query.(core.ParamsGetStatsListRoot).ParamsGetStatsList
+	hc.requests = append(hc.requests, params)
+
+	start := params.StartPosition
+	if start == 0 {
+		start = 1
+	}
+	end := len(hc.ids)
+	if params.Limit > 0 && start-1+params.Limit < end {
+		end = start - 1 + params.Limit
+	}
+
+	var b strings.Builder
+	b.WriteString(`<GET_STATS_LIST><RESULT><STATUS>0</STATUS></RESULT><DATALIST_INF>`)
+	fmt.Fprintf(&b, `<NUMBER>%d</NUMBER><RESULT_INF><FROM_NUMBER>%d</FROM_NUMBER><TO_NUMBER>%d</TO_NUMBER>`, len(hc.ids), start, end)
+	if end < len(hc.ids) {
+		fmt.Fprintf(&b, `<NEXT_KEY>%d</NEXT_KEY>`, end+1)
+	}
+	b.WriteString(`</RESULT_INF>`)
+	for _, id := range hc.ids[start-1 : end] {
+		fmt.Fprintf(&b, `<TABLE_INF id="%s"/>`, id)
+	}
+	b.WriteString(`</DATALIST_INF></GET_STATS_LIST>`)
+
+	return http.StatusOK, []byte(b.String()), nil
+}
+
+func TestStatsListPager(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		maxItems     int
+		want         []string
+		wantRequests []core.ParamsGetStatsList
+	}{
+		{
+			name:  "All",
+			limit: 2,
+			want:  []string{"01", "02", "03", "04", "05"},
+			wantRequests: []core.ParamsGetStatsList{
+				{Limit: 2},
+				{StartPosition: 3, Limit: 2},
+				{StartPosition: 5, Limit: 2},
+			},
+		},
+		{
+			name:     "MaxItems",
+			limit:    2,
+			maxItems: 3,
+			want:     []string{"01", "02", "03"},
+			wantRequests: []core.ParamsGetStatsList{
+				{Limit: 2},
+				{StartPosition: 3, Limit: 1},
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := statsListHttpClient{ids: []string{"01", "02", "03", "04", "05"}}
+			ac := core.NewApiClient(estattest.ClientFunc(hc.do), core.CommonParams{})
+
+			p := ac.NewStatsListPager(core.ParamsGetStatsList{Limit: tt.limit}, tt.maxItems)
+			tables, err := p.All(ctx)
+			if err != nil {
+				t.Fatalf("All() error = %v", err)
+			}
+
+			var got []string
+			for _, table := range tables {
+				got = append(got, table.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(hc.requests, tt.wantRequests) {
+				t.Errorf("All() requests = %+v, want %+v", hc.requests, tt.wantRequests)
+			}
+			if p.Total() != 5 {
+				t.Errorf("Total() = %d, want %d", p.Total(), 5)
+			}
+			if _, err := p.Next(ctx); err != io.EOF {
+				t.Errorf("Next() error = %v, want %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestDataCatalogPager(t *testing.T) {
+	ctx := context.Background()
+	hc := mockHttpClient{}
+	ac := core.NewApiClient(&hc, core.CommonParams{})
+
+	p := ac.NewDataCatalogPager(core.ParamsGetDataCatalog{}, 1)
+
+	got, err := p.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "000001120179" {
+		t.Errorf("Next() = %v", got)
+	}
+	if p.Total() != 42198 {
+		t.Errorf("Total() = %d, want %d", p.Total(), 42198)
+	}
+	if _, err := p.Next(ctx); err != io.EOF {
+		t.Errorf("Next() error = %v, want %v", err, io.EOF)
+	}
+}
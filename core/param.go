@@ -0,0 +1,289 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 言語 (CommonParams.Lang)
+type Lang string
+
+const (
+	// 日本語 (省略値)
+	LangJapanese Lang = "J"
+
+	// 英語
+	LangEnglish Lang = "E"
+)
+
+// 値が正しいかを返します。空文字列 (省略) も正しい値です。
+func (l Lang) Valid() bool {
+	return l == "" || l == LangJapanese || l == LangEnglish
+}
+
+// 取得の有無を指定するフラグ (MetaGetFlg、CntGetFlg、ExplanationGetFlg、AnnotationGetFlg)
+type Flag string
+
+const (
+	// 取得する
+	FlagYes Flag = "Y"
+
+	// 取得しない
+	FlagNo Flag = "N"
+)
+
+// b が true なら FlagYes、false なら FlagNo を返します。
+func BoolFlag(b bool) Flag {
+	if b {
+		return FlagYes
+	}
+	return FlagNo
+}
+
+// 値が正しいかを返します。空文字列 (省略) も正しい値です。
+func (f Flag) Valid() bool {
+	return f == "" || f == FlagYes || f == FlagNo
+}
+
+// 検索データ種別 (ParamsGetStatsList.SearchKind)
+type SearchKind int
+
+const (
+	// 統計情報 (省略値)
+	SearchKindStats SearchKind = 1
+
+	// 小地域・地域メッシュ
+	SearchKindSmallArea SearchKind = 2
+)
+
+// 値が正しいかを返します。0 (省略) も正しい値です。
+func (k SearchKind) Valid() bool {
+	return k == 0 || k == SearchKindStats || k == SearchKindSmallArea
+}
+
+// 集計地域区分 (ParamsGetStatsList.CollectArea など)
+type CollectArea int
+
+const (
+	// 全国
+	CollectAreaNational CollectArea = 1
+
+	// 都道府県
+	CollectAreaPrefecture CollectArea = 2
+
+	// 市区町村
+	CollectAreaMunicipality CollectArea = 3
+)
+
+// 値が正しいかを返します。0 (省略) も正しい値です。
+func (a CollectArea) Valid() bool {
+	return a >= 0 && a <= CollectAreaMunicipality
+}
+
+// 処理モード (ParamsPostDataset.ProcessMode)
+type ProcessMode string
+
+const (
+	// 登録・更新 (省略値)
+	ProcessModeRegister ProcessMode = "E"
+
+	// 削除
+	ProcessModeDelete ProcessMode = "D"
+)
+
+// 値が正しいかを返します。空文字列 (省略) も正しい値です。
+func (m ProcessMode) Valid() bool {
+	return m == "" || m == ProcessModeRegister || m == ProcessModeDelete
+}
+
+// 公開の有無 (ParamsPostDataset.OpenSpecified)
+type OpenSpecified string
+
+const (
+	// 公開しない (省略値)
+	OpenSpecifiedPrivate OpenSpecified = "0"
+
+	// 公開する
+	OpenSpecifiedPublic OpenSpecified = "1"
+)
+
+// 値が正しいかを返します。空文字列 (省略) も正しい値です。
+func (o OpenSpecified) Valid() bool {
+	return o == "" || o == OpenSpecifiedPrivate || o == OpenSpecifiedPublic
+}
+
+// 統計分野
+//
+// 大分類は 2 桁、小分類は大分類に 2 桁を付けた 4 桁のコードです。
+//
+// https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_1
+type StatsField string
+
+// 統計分野 (大分類)
+const (
+	StatsFieldLandAndWeather          StatsField = "01"
+	StatsFieldPopulationAndHouseholds StatsField = "02"
+	StatsFieldLaborAndWages           StatsField = "03"
+	StatsFieldAgricultureAndFisheries StatsField = "04"
+	StatsFieldMiningAndManufacturing  StatsField = "05"
+	StatsFieldCommerceAndServices     StatsField = "06"
+	StatsFieldEconomyAndHouseholds    StatsField = "07"
+	StatsFieldHousingAndConstruction  StatsField = "08"
+	StatsFieldEnergyAndWater          StatsField = "09"
+	StatsFieldTransportAndTourism     StatsField = "10"
+	StatsFieldInformationAndScience   StatsField = "11"
+	StatsFieldEducationAndCulture     StatsField = "12"
+	StatsFieldGovernmentAndFinance    StatsField = "13"
+	StatsFieldJusticeAndEnvironment   StatsField = "14"
+	StatsFieldSocialSecurityAndHealth StatsField = "15"
+	StatsFieldInternational           StatsField = "16"
+	StatsFieldOthers                  StatsField = "99"
+)
+
+var statsFieldNames = map[StatsField]string{
+	StatsFieldLandAndWeather:          "国土・気象",
+	StatsFieldPopulationAndHouseholds: "人口・世帯",
+	StatsFieldLaborAndWages:           "労働・賃金",
+	StatsFieldAgricultureAndFisheries: "農林水産業",
+	StatsFieldMiningAndManufacturing:  "鉱工業",
+	StatsFieldCommerceAndServices:     "商業・サービス業",
+	StatsFieldEconomyAndHouseholds:    "企業・家計・経済",
+	StatsFieldHousingAndConstruction:  "住宅・土地・建設",
+	StatsFieldEnergyAndWater:          "エネルギー・水",
+	StatsFieldTransportAndTourism:     "運輸・観光",
+	StatsFieldInformationAndScience:   "情報通信・科学技術",
+	StatsFieldEducationAndCulture:     "教育・文化・スポーツ・生活",
+	StatsFieldGovernmentAndFinance:    "行財政",
+	StatsFieldJusticeAndEnvironment:   "司法・安全・環境",
+	StatsFieldSocialSecurityAndHealth: "社会保障・衛生",
+	StatsFieldInternational:           "国際",
+	StatsFieldOthers:                  "その他",
+}
+
+// 統計分野の大分類をコードの順に返します。
+func StatsFields() []StatsField {
+	fields := make([]StatsField, 0, len(statsFieldNames))
+	for f := range statsFieldNames {
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i] < fields[j]
+	})
+	return fields
+}
+
+// 大分類を返します。
+func (f StatsField) Major() StatsField {
+	if len(f) < 2 {
+		return f
+	}
+	return f[:2]
+}
+
+// 大分類の名前を返します。不明な分野の場合は空文字列を返します。
+func (f StatsField) Name() string {
+	return statsFieldNames[f.Major()]
+}
+
+// 値が正しいかを返します。空文字列 (省略) も正しい値です。
+//
+// 小分類は 4 桁の数字で、大分類が正しいことだけを確かめます。
+func (f StatsField) Valid() bool {
+	if f == "" {
+		return true
+	}
+	if len(f) != 2 && len(f) != 4 {
+		return false
+	}
+	for _, r := range f {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	_, ok := statsFieldNames[f.Major()]
+	return ok
+}
+
+// パラメータのエラー
+//
+// errors.Is で ErrInvalidParameter と比較できます。
+type ParamError struct {
+	// パラメータ名 (lang、metaGetFlg など) と値
+	Param string
+	Value string
+
+	Reason string
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("e-stat: %s=%q: %s", e.Param, e.Value, e.Reason)
+}
+
+func (e *ParamError) Is(target error) bool {
+	return target == ErrInvalidParameter
+}
+
+// 複数のパラメータのエラー
+type ParamErrors []*ParamError
+
+func (e ParamErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ParamErrors) Is(target error) bool {
+	return target == ErrInvalidParameter
+}
+
+// パラメータを検証する。
+type paramValidator struct {
+	errs ParamErrors
+}
+
+func (v *paramValidator) check(ok bool, param string, value any, reason string) {
+	if !ok {
+		v.errs = append(v.errs, &ParamError{Param: param, Value: fmt.Sprint(value), Reason: reason})
+	}
+}
+
+func (v *paramValidator) flag(param string, f Flag) {
+	v.check(f.Valid(), param, f, `must be "Y" or "N"`)
+}
+
+func (v *paramValidator) nonNegative(param string, n int) {
+	v.check(n >= 0, param, n, "must not be negative")
+}
+
+func (v *paramValidator) replaceSpChar(n int) {
+	v.check(n >= ReplaceSpCharNone && n <= ReplaceSpCharNA, "replaceSpChar", n, "must be between 0 and 3")
+}
+
+func (v *paramValidator) collectArea(a CollectArea) {
+	v.check(a.Valid(), "collectArea", a, "must be 1, 2 or 3")
+}
+
+func (v *paramValidator) statsField(f StatsField) {
+	v.check(f.Valid(), "statsField", f, "unknown statistical field")
+}
+
+func (v *paramValidator) sectionHeaderFlg(s string) {
+	v.check(s == "" || s == "1" || s == "2", "sectionHeaderFlg", s, `must be "1" or "2"`)
+}
+
+func (v *paramValidator) err() error {
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// パラメータを検証します。
+func (p CommonParams) Validate() error {
+	var v paramValidator
+	v.check(p.Lang.Valid(), "lang", p.Lang, `must be "J" or "E"`)
+	return v.err()
+}
@@ -0,0 +1,159 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-querystring/query"
+	"github.com/itok01/e-stat-go/core"
+)
+
+func TestBoolFlag(t *testing.T) {
+	if got := core.BoolFlag(true); got != core.FlagYes {
+		t.Errorf("BoolFlag(true) = %q", got)
+	}
+	if got := core.BoolFlag(false); got != core.FlagNo {
+		t.Errorf("BoolFlag(false) = %q", got)
+	}
+}
+
+func TestStatsField(t *testing.T) {
+	tests := []struct {
+		field core.StatsField
+		valid bool
+		name  string
+	}{
+		{"", true, ""},
+		{core.StatsFieldPopulationAndHouseholds, true, "人口・世帯"},
+		{"0201", true, "人口・世帯"},
+		{core.StatsFieldOthers, true, "その他"},
+		{"17", false, ""},
+		{"2", false, ""},
+		{"02a1", false, "人口・世帯"},
+		{"020101", false, "人口・世帯"},
+	}
+	for _, tt := range tests {
+		if got := tt.field.Valid(); got != tt.valid {
+			t.Errorf("StatsField(%q).Valid() = %v, want %v", tt.field, got, tt.valid)
+		}
+		if got := tt.field.Name(); got != tt.name {
+			t.Errorf("StatsField(%q).Name() = %q, want %q", tt.field, got, tt.name)
+		}
+	}
+
+	fields := core.StatsFields()
+	if len(fields) != 17 || fields[0] != core.StatsFieldLandAndWeather || fields[16] != core.StatsFieldOthers {
+		t.Errorf("StatsFields() = %v", fields)
+	}
+
+	values, err := query.Values(core.ParamsGetStatsList{StatsField: "0201", CollectArea: core.CollectAreaPrefecture})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values.Get("statsField"); got != "0201" {
+		t.Errorf("statsField = %q, want %q", got, "0201")
+	}
+	if got := values.Get("collectArea"); got != "2" {
+		t.Errorf("collectArea = %q, want %q", got, "2")
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params interface{ Validate() error }
+		want   []string
+	}{
+		{
+			name:   "CommonParams",
+			params: core.CommonParams{AppID: "test", Lang: "j"},
+			want:   []string{"lang"},
+		},
+		{
+			name:   "ParamsGetStatsList",
+			params: core.ParamsGetStatsList{StatsField: "02", SearchKind: core.SearchKindSmallArea, CollectArea: core.CollectAreaMunicipality, ExplanationGetFlg: core.FlagYes},
+		},
+		{
+			name:   "ParamsGetStatsListInvalid",
+			params: core.ParamsGetStatsList{StatsField: "2", SearchKind: 3, CollectArea: 4, ExplanationGetFlg: "y", Limit: -1},
+			want:   []string{"statsField", "searchKind", "collectArea", "explanationGetFlg", "limit"},
+		},
+		{
+			name:   "ParamsGetStatsData",
+			params: core.ParamsGetStatsData{StatsDataId: "0003109741", MetaGetFlg: "Yes", CntGetFlg: core.FlagNo, ReplaceSpChar: 4},
+			want:   []string{"metaGetFlg", "replaceSpChar"},
+		},
+		{
+			name:   "ParamsGetSimpleStatsData",
+			params: core.ParamsGetSimpleStatsData{ParamsGetStatsData: core.ParamsGetStatsData{AnnotationGetFlg: "X"}, SectionHeaderFlg: "Y"},
+			want:   []string{"annotationGetFlg", "sectionHeaderFlg"},
+		},
+		{
+			name:   "ParamsGetStatsDatas",
+			params: core.ParamsGetStatsDatas{ExplanationGetFlg: core.BoolFlag(true), SectionHeaderFlg: "3"},
+			want:   []string{"sectionHeaderFlg"},
+		},
+		{
+			name:   "ParamsPostDataset",
+			params: core.ParamsPostDataset{OpenSpecified: "2", ProcessMode: core.ProcessModeDelete},
+			want:   []string{"openSpecified", "dataSetId"},
+		},
+		{
+			name:   "ParamsGetDatasetList",
+			params: core.ParamsGetDatasetList{CollectArea: -1},
+			want:   []string{"collectArea"},
+		},
+		{
+			name:   "ParamsGetDataCatalog",
+			params: core.ParamsGetDataCatalog{StatsField: "1701"},
+			want:   []string{"statsField"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var errs core.ParamErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() error = %v, want ParamErrors", err)
+			}
+			if !errors.Is(err, core.ErrInvalidParameter) {
+				t.Errorf("errors.Is(%v, ErrInvalidParameter) = false", err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Param)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("params = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApiClientValidate(t *testing.T) {
+	// リクエストの前に検証するため HttpClient は使われない
+	ac := core.NewApiClient(nil, core.CommonParams{AppID: "test"})
+	if _, err := ac.GetStatsList(context.Background(), core.ParamsGetStatsList{SearchKind: 5}); !errors.Is(err, core.ErrInvalidParameter) {
+		t.Errorf("GetStatsList() error = %v, want ErrInvalidParameter", err)
+	}
+
+	specs := make([]core.StatsDatasSpec, core.MaxStatsDatasSpecs+1)
+	if _, err := ac.GetStatsDatas(context.Background(), core.ParamsGetStatsDatas{}, specs); !errors.Is(err, core.ErrInvalidParameter) {
+		t.Errorf("GetStatsDatas() error = %v, want ErrInvalidParameter", err)
+	}
+
+	ac = core.NewApiClient(nil, core.CommonParams{AppID: "test", Lang: "JP"})
+	if _, err := ac.GetMetaInfoList(context.Background(), core.ParamsGetMetaInfoList{StatsDataId: "0003109741"}); !errors.Is(err, core.ErrInvalidParameter) {
+		t.Errorf("GetMetaInfoList() error = %v, want ErrInvalidParameter", err)
+	}
+}
@@ -0,0 +1,411 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 期間の長さ
+type Granularity int
+
+const (
+	// 月数が 1、3、6、12 以外
+	GranularityUnknown Granularity = iota
+
+	GranularityMonth
+
+	GranularityQuarter
+
+	GranularityHalfYear
+
+	GranularityYear
+)
+
+func (g Granularity) String() string {
+	switch g {
+	case GranularityMonth:
+		return "month"
+	case GranularityQuarter:
+		return "quarter"
+	case GranularityHalfYear:
+		return "half-year"
+	case GranularityYear:
+		return "year"
+	}
+	return "unknown"
+}
+
+// 月数を返す。
+func (g Granularity) months() int {
+	switch g {
+	case GranularityMonth:
+		return 1
+	case GranularityQuarter:
+		return 3
+	case GranularityHalfYear:
+		return 6
+	case GranularityYear:
+		return 12
+	}
+	return 0
+}
+
+func granularityOf(months int) Granularity {
+	switch months {
+	case 1:
+		return GranularityMonth
+	case 3:
+		return GranularityQuarter
+	case 6:
+		return GranularityHalfYear
+	case 12:
+		return GranularityYear
+	}
+	return GranularityUnknown
+}
+
+// 年度の開始月
+const FiscalYearStart = time.April
+
+// 時間軸 (time) のコードが表す期間
+//
+// Start と End は UTC の月初で、End は期間に含みません。
+type Period struct {
+	Code        string
+	Granularity Granularity
+
+	// 年度 (4 月始まり) の期間
+	Fiscal bool
+
+	Start time.Time
+	End   time.Time
+}
+
+// 時間軸のコードを解析します。
+//
+// コードは 10 桁で、先頭 4 桁が年、続く 2 桁が種別、残り 4 桁が開始月と終了月です。
+//
+// ・2022000000：2022年
+//
+// ・2022100000：2022年度
+//
+// ・2022010000、2022020000：2022年上期、下期
+//
+// ・2022000101：2022年1月
+//
+// ・2022000103：2022年1～3月期
+//
+// ・2022100103：2022年度1～3月期 (2023年1～3月)
+func ParsePeriod(code string) (Period, error) {
+	if len(code) != 10 {
+		return Period{}, fmt.Errorf("e-stat: invalid time code %q", code)
+	}
+	n := make([]int, 4)
+	for i, s := range []string{code[:4], code[4:6], code[6:8], code[8:]} {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return Period{}, fmt.Errorf("e-stat: invalid time code %q", code)
+		}
+		n[i] = v
+	}
+	year, kind, from, to := n[0], n[1], n[2], n[3]
+
+	var p Period
+	switch {
+	case kind == 0 && from == 0 && to == 0:
+		p = newPeriod(year, 1, 12, false)
+	case kind == 10 && from == 0 && to == 0:
+		p = newPeriod(year, int(FiscalYearStart), 12, true)
+	case (kind == 1 || kind == 2) && from == 0 && to == 0:
+		p = newPeriod(year, kind*6-5, 6, false)
+	case (kind == 0 || kind == 10) && from >= 1 && from <= 12 && to >= 1 && to <= 12:
+		fiscal := kind == 10
+		start, end := from, to
+		if fiscal {
+			start, end = fiscalMonth(from), fiscalMonth(to)
+		}
+		if end < start {
+			return Period{}, fmt.Errorf("e-stat: invalid time code %q", code)
+		}
+		p = newPeriod(year, start, end-start+1, fiscal)
+	default:
+		return Period{}, fmt.Errorf("e-stat: unsupported time code %q", code)
+	}
+
+	p.Code = code
+	return p, nil
+}
+
+// 年度の月を、年度の開始年の 1 月からの月数に変換する。(4 月は 4、3 月は 15)
+func fiscalMonth(month int) int {
+	if month < int(FiscalYearStart) {
+		return month + 12
+	}
+	return month
+}
+
+// year 年 month 月 (13 以上は翌年) から months か月の期間を返す。
+func newPeriod(year int, month int, months int, fiscal bool) Period {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return Period{
+		Granularity: granularityOf(months),
+		Fiscal:      fiscal,
+		Start:       start,
+		End:         start.AddDate(0, months, 0),
+	}
+}
+
+// t を含む、g の長さの期間を返します。
+//
+// fiscal が true の場合は年度に合わせた期間を返します。
+func PeriodOf(t time.Time, g Granularity, fiscal bool) Period {
+	months := g.months()
+	if months == 0 {
+		months = 1
+		g = GranularityMonth
+	}
+
+	offset := 1
+	if fiscal {
+		offset = int(FiscalYearStart)
+	}
+	index := t.Year()*12 + int(t.Month()) - offset
+	index -= index % months
+	year, month := index/12, index%12+offset
+
+	p := newPeriod(year, month, months, fiscal && g != GranularityMonth)
+	p.Code = p.code()
+	return p
+}
+
+// 期間の開始年を返す。年度の場合は年度の年を返す。
+func (p Period) startYear() int {
+	year := p.Start.Year()
+	if p.Fiscal && p.Start.Month() < FiscalYearStart {
+		year--
+	}
+	return year
+}
+
+// 期間からコードを作る。
+func (p Period) code() string {
+	year := p.startYear()
+	from, to := int(p.Start.Month()), int(p.End.AddDate(0, -1, 0).Month())
+
+	switch {
+	case p.Granularity == GranularityYear && p.Fiscal && from == int(FiscalYearStart):
+		return fmt.Sprintf("%04d100000", year)
+	case p.Granularity == GranularityYear && !p.Fiscal && from == 1:
+		return fmt.Sprintf("%04d000000", year)
+	case p.Granularity == GranularityHalfYear && !p.Fiscal && (from == 1 || from == 7):
+		return fmt.Sprintf("%04d%02d0000", year, from/6+1)
+	case p.Fiscal:
+		return fmt.Sprintf("%04d10%02d%02d", year, from, to)
+	}
+	return fmt.Sprintf("%04d00%02d%02d", year, from, to)
+}
+
+// 期間を含む、g の長さの期間を返します。
+func (p Period) Truncate(g Granularity, fiscal bool) Period {
+	return PeriodOf(p.Start, g, fiscal)
+}
+
+// n 期後の期間を返します。
+func (p Period) Add(n int) Period {
+	months := p.Months()
+	q := Period{
+		Granularity: p.Granularity,
+		Fiscal:      p.Fiscal,
+		Start:       p.Start.AddDate(0, n*months, 0),
+		End:         p.End.AddDate(0, n*months, 0),
+	}
+	q.Code = q.code()
+	return q
+}
+
+// 期間の月数を返します。
+func (p Period) Months() int {
+	return (p.End.Year()-p.Start.Year())*12 + int(p.End.Month()) - int(p.Start.Month())
+}
+
+// t が期間に含まれるかどうか
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// 開始、終了の順に比較し、p が q より前なら -1、後なら 1、同じなら 0 を返します。
+func (p Period) Compare(q Period) int {
+	switch {
+	case p.Start.Before(q.Start):
+		return -1
+	case p.Start.After(q.Start):
+		return 1
+	case p.End.Before(q.End):
+		return -1
+	case p.End.After(q.End):
+		return 1
+	}
+	return 0
+}
+
+// p が q より前かどうか
+func (p Period) Before(q Period) bool {
+	return p.Compare(q) < 0
+}
+
+// "2022"、"FY2022"、"2022-04"、"2022Q1"、"FY2022H1" のような表記を返します。
+func (p Period) String() string {
+	prefix := ""
+	if p.Fiscal {
+		prefix = "FY"
+	}
+	year := p.startYear()
+
+	offset := 1
+	if p.Fiscal {
+		offset = int(FiscalYearStart)
+	}
+	index := (int(p.Start.Month()) - offset + 12) % 12
+
+	switch p.Granularity {
+	case GranularityYear:
+		if index == 0 {
+			return fmt.Sprintf("%s%04d", prefix, year)
+		}
+	case GranularityHalfYear:
+		if index%6 == 0 {
+			return fmt.Sprintf("%s%04dH%d", prefix, year, index/6+1)
+		}
+	case GranularityQuarter:
+		if index%3 == 0 {
+			return fmt.Sprintf("%s%04dQ%d", prefix, year, index/3+1)
+		}
+	case GranularityMonth:
+		return p.Start.Format("2006-01")
+	}
+	return p.Start.Format("2006-01") + "/" + p.End.AddDate(0, -1, 0).Format("2006-01")
+}
+
+// 時間軸の期間を返します。
+func (r TableRecord) Period() (Period, error) {
+	d, ok := r.Dim("time")
+	if !ok {
+		return Period{}, fmt.Errorf("e-stat: record has no time dimension")
+	}
+	return ParsePeriod(d.Code)
+}
+
+// レコードを時間軸の期間の順に並べ替えます。期間を解析できないレコードは後ろに並べます。
+func (t *Table) SortByPeriod() {
+	type item struct {
+		record TableRecord
+		period Period
+		ok     bool
+	}
+	items := make([]item, len(t.Records))
+	for i, r := range t.Records {
+		p, err := r.Period()
+		items[i] = item{record: r, period: p, ok: err == nil}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ok != items[j].ok {
+			return items[i].ok
+		}
+		return items[i].ok && items[i].period.Before(items[j].period)
+	})
+
+	for i, item := range items {
+		t.Records[i] = item.record
+	}
+}
+
+// 時間軸を g の長さの期間にまとめた Table を返します。
+//
+// 時間軸以外の分類事項ごとに agg で値をまとめます。agg が nil の場合は AggregateSum を使います。
+// 期間の一部の値がない、または欠損値を含む場合は、その期間を出力しません。
+func (t *Table) Resample(g Granularity, fiscal bool, agg Aggregation, replaceSpChar int) *Table {
+	if agg == nil {
+		agg = AggregateSum
+	}
+
+	resampled := *t
+	resampled.Records = nil
+
+	ti := t.dimIndex("time")
+	if ti < 0 {
+		return &resampled
+	}
+	numbers := t.Numbers(replaceSpChar)
+
+	type bucket struct {
+		record  TableRecord
+		period  Period
+		values  []float64
+		months  int
+		missing bool
+		seen    map[string]bool
+	}
+	var buckets []*bucket
+	index := map[string]*bucket{}
+
+	for i, r := range t.Records {
+		p, err := ParsePeriod(r.Dims[ti].Code)
+		if err != nil {
+			continue
+		}
+		target := p.Truncate(g, fiscal)
+		if p.Months() > target.Months() || p.End.After(target.End) {
+			continue
+		}
+
+		keys := make([]string, len(r.Dims))
+		for n, d := range r.Dims {
+			keys[n] = d.Code
+		}
+		keys[ti] = target.Code
+		key := strings.Join(keys, "\x00")
+
+		b, ok := index[key]
+		if !ok {
+			record := TableRecord{
+				Dims: append([]TableDim{}, r.Dims...),
+				Unit: r.Unit,
+			}
+			record.Dims[ti] = TableDim{
+				ID:      "time",
+				ObjName: r.Dims[ti].ObjName,
+				Code:    target.Code,
+				Name:    target.String(),
+			}
+			b = &bucket{record: record, period: target, seen: map[string]bool{}}
+			index[key] = b
+			buckets = append(buckets, b)
+		}
+
+		if b.seen[p.Code] {
+			continue
+		}
+		b.seen[p.Code] = true
+		b.months += p.Months()
+
+		if !numbers[i].Valid {
+			b.missing = true
+			continue
+		}
+		b.values = append(b.values, numbers[i].Float)
+	}
+
+	for _, b := range buckets {
+		if b.missing || b.months != b.period.Months() {
+			continue
+		}
+		b.record.Value = strconv.FormatFloat(agg(b.values), 'f', -1, 64)
+		resampled.Records = append(resampled.Records, b.record)
+	}
+
+	return &resampled
+}
@@ -0,0 +1,158 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    core.Period
+		str     string
+		wantErr bool
+	}{
+		{
+			code: "2022000000",
+			want: core.Period{Code: "2022000000", Granularity: core.GranularityYear, Start: month(2022, time.January), End: month(2023, time.January)},
+			str:  "2022",
+		},
+		{
+			code: "2022100000",
+			want: core.Period{Code: "2022100000", Granularity: core.GranularityYear, Fiscal: true, Start: month(2022, time.April), End: month(2023, time.April)},
+			str:  "FY2022",
+		},
+		{
+			code: "2022020000",
+			want: core.Period{Code: "2022020000", Granularity: core.GranularityHalfYear, Start: month(2022, time.July), End: month(2023, time.January)},
+			str:  "2022H2",
+		},
+		{
+			code: "2022000404",
+			want: core.Period{Code: "2022000404", Granularity: core.GranularityMonth, Start: month(2022, time.April), End: month(2022, time.May)},
+			str:  "2022-04",
+		},
+		{
+			code: "2022001212",
+			want: core.Period{Code: "2022001212", Granularity: core.GranularityMonth, Start: month(2022, time.December), End: month(2023, time.January)},
+			str:  "2022-12",
+		},
+		{
+			code: "2022000103",
+			want: core.Period{Code: "2022000103", Granularity: core.GranularityQuarter, Start: month(2022, time.January), End: month(2022, time.April)},
+			str:  "2022Q1",
+		},
+		{
+			code: "2022100103",
+			want: core.Period{Code: "2022100103", Granularity: core.GranularityQuarter, Fiscal: true, Start: month(2023, time.January), End: month(2023, time.April)},
+			str:  "FY2022Q4",
+		},
+		{
+			code: "2022000105",
+			want: core.Period{Code: "2022000105", Start: month(2022, time.January), End: month(2022, time.June)},
+			str:  "2022-01/2022-05",
+		},
+		{code: "2022", wantErr: true},
+		{code: "2022001201", wantErr: true},
+		{code: "2022050000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := core.ParsePeriod(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePeriod() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.str {
+				t.Errorf("String() = %s, want %s", got.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestPeriodConversion(t *testing.T) {
+	p, err := core.ParsePeriod("2023000202")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  core.Period
+		want string
+	}{
+		{name: "Quarter", got: p.Truncate(core.GranularityQuarter, false), want: "2023000103"},
+		{name: "HalfYear", got: p.Truncate(core.GranularityHalfYear, false), want: "2023010000"},
+		{name: "Year", got: p.Truncate(core.GranularityYear, false), want: "2023000000"},
+		{name: "FiscalYear", got: p.Truncate(core.GranularityYear, true), want: "2022100000"},
+		{name: "FiscalQuarter", got: p.Truncate(core.GranularityQuarter, true), want: "2022100103"},
+		{name: "AddMonth", got: p.Add(11), want: "2024000101"},
+		{name: "AddFiscalYear", got: p.Truncate(core.GranularityYear, true).Add(-1), want: "2021100000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Code != tt.want {
+				t.Errorf("Code = %s, want %s", tt.got.Code, tt.want)
+			}
+			if q, err := core.ParsePeriod(tt.got.Code); err != nil || !reflect.DeepEqual(q, tt.got) {
+				t.Errorf("ParsePeriod(%s) = %+v, want %+v", tt.got.Code, q, tt.got)
+			}
+		})
+	}
+
+	year := p.Truncate(core.GranularityYear, false)
+	if !year.Contains(p.Start) || year.Contains(year.End) {
+		t.Errorf("Contains() is not half-open")
+	}
+	if !year.Before(p) || p.Compare(year) != 1 || p.Compare(p) != 0 {
+		t.Errorf("Compare() ordering is wrong")
+	}
+}
+
+func TestTableResample(t *testing.T) {
+	var values []core.DataInfValue
+	for _, code := range []string{"2022001012", "2022000406", "2022000709", "2022000103", "2023000103", "2023000406"} {
+		values = append(values, core.DataInfValue{Area: "00000", Time: code, Value: "1"})
+	}
+	values = append(values, core.DataInfValue{Area: "13000", Time: "2022000103", Value: "2"})
+	table := core.NewTable(core.ResponseGetStatsDataStatisticalData{Data: core.DataInf{Value: values}})
+
+	table.SortByPeriod()
+	if got := table.Codes("time")[:4]; !reflect.DeepEqual(got, []string{"2022000103", "2022000103", "2022000406", "2022000709"}) {
+		t.Errorf("SortByPeriod() = %v", got)
+	}
+
+	resampled := table.Resample(core.GranularityYear, false, core.AggregateSum, core.ReplaceSpCharNone)
+	want := []core.TableRecord{
+		{
+			Dims: []core.TableDim{
+				{ID: "area", Code: "00000"},
+				{ID: "time", Code: "2022000000", Name: "2022"},
+			},
+			Value: "4",
+		},
+	}
+	if !reflect.DeepEqual(resampled.Records, want) {
+		t.Errorf("Resample() = %+v, want %+v", resampled.Records, want)
+	}
+	if table.Len() != 7 {
+		t.Errorf("Resample() modified the table")
+	}
+	if got := table.Resample(core.GranularityYear, false, nil, core.ReplaceSpCharNone); !reflect.DeepEqual(got.Records, want) {
+		t.Errorf("Resample() with nil aggregation = %+v, want %+v", got.Records, want)
+	}
+}
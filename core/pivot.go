@@ -0,0 +1,248 @@
+package core
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 集計表の設定
+type PivotOptions struct {
+	// 表側 (行) と表頭 (列) の分類事項の ID
+	Rows    string
+	Columns string
+
+	// 分類事項の ID と、固定するコード
+	Filters map[string]string
+
+	// すべての分類事項のコードが同じ値が重複している場合のまとめ方。nil の場合は AggregateSum を使います。
+	Aggregation Aggregation
+
+	// リクエストの ReplaceSpChar
+	ReplaceSpChar int
+}
+
+// 表側と表頭に分類事項を並べた集計表
+type PivotTable struct {
+	Rows    []TableDim
+	Columns []TableDim
+
+	// Cells[i][j] は Rows[i] と Columns[j] の値です。値がない場合は Number のゼロ値です。
+	Cells [][]Number
+}
+
+// 表側と表頭に分類事項を並べた集計表を作ります。
+//
+// 表側と表頭の並びは CLASS_INF の順番です。
+// 表側、表頭、opts.Filters のいずれにもない分類事項に複数のコードがある場合はエラーを返します。
+func (t *Table) Pivot(opts PivotOptions) (*PivotTable, error) {
+	ri, ci := t.dimIndex(opts.Rows), t.dimIndex(opts.Columns)
+	if ri < 0 {
+		return nil, fmt.Errorf("e-stat: unknown dimension %q", opts.Rows)
+	}
+	if ci < 0 {
+		return nil, fmt.Errorf("e-stat: unknown dimension %q", opts.Columns)
+	}
+	if ri == ci {
+		return nil, fmt.Errorf("e-stat: rows and columns are the same dimension %q", opts.Rows)
+	}
+
+	filters := map[int]string{}
+	for id, code := range opts.Filters {
+		i := t.dimIndex(id)
+		if i < 0 {
+			return nil, fmt.Errorf("e-stat: unknown dimension %q", id)
+		}
+		filters[i] = code
+	}
+
+	aggregation := opts.Aggregation
+	if aggregation == nil {
+		aggregation = AggregateSum
+	}
+
+	records := make([]int, 0, len(t.Records))
+	unfixed := map[int]map[string]bool{}
+records:
+	for i, r := range t.Records {
+		for fi, code := range filters {
+			if r.Dims[fi].Code != code {
+				continue records
+			}
+		}
+		records = append(records, i)
+
+		for di, d := range r.Dims {
+			if _, ok := filters[di]; ok || di == ri || di == ci {
+				continue
+			}
+			if unfixed[di] == nil {
+				unfixed[di] = map[string]bool{}
+			}
+			unfixed[di][d.Code] = true
+		}
+	}
+
+	// 固定していない分類事項をまとめると、単位の異なる値や上位の階層の合計を足し合わせてしまう
+	var ids []string
+	for di, id := range t.dims {
+		if len(unfixed[di]) > 1 {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		return nil, fmt.Errorf("e-stat: dimensions %s have multiple codes; add them to Rows, Columns or Filters", strings.Join(ids, ", "))
+	}
+
+	numbers := t.Numbers(opts.ReplaceSpChar)
+
+	type cell struct {
+		values  []float64
+		missing *Number
+		unit    string
+	}
+	cells := map[[2]string]*cell{}
+	rowCodes, columnCodes := map[string]bool{}, map[string]bool{}
+
+	for _, i := range records {
+		r := t.Records[i]
+		row, column := r.Dims[ri].Code, r.Dims[ci].Code
+		rowCodes[row] = true
+		columnCodes[column] = true
+
+		key := [2]string{row, column}
+		c, ok := cells[key]
+		if !ok {
+			c = &cell{unit: numbers[i].Unit}
+			cells[key] = c
+		}
+		if !numbers[i].Valid {
+			if c.missing == nil {
+				n := numbers[i]
+				c.missing = &n
+			}
+			continue
+		}
+		c.values = append(c.values, numbers[i].Float)
+	}
+
+	p := &PivotTable{
+		Rows:    t.pivotHeaders(ri, rowCodes),
+		Columns: t.pivotHeaders(ci, columnCodes),
+	}
+	p.Cells = make([][]Number, len(p.Rows))
+	for i, row := range p.Rows {
+		p.Cells[i] = make([]Number, len(p.Columns))
+		for j, column := range p.Columns {
+			c, ok := cells[[2]string{row.Code, column.Code}]
+			switch {
+			case !ok:
+			case c.missing != nil:
+				p.Cells[i][j] = *c.missing
+			default:
+				p.Cells[i][j] = Number{Valid: true, Float: aggregation(c.values), Unit: c.unit}
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// di 番目の分類事項のうち codes に含まれるものを CLASS_INF の順番で返す。
+func (t *Table) pivotHeaders(di int, codes map[string]bool) []TableDim {
+	id := t.dims[di]
+
+	var headers []TableDim
+	seen := map[string]bool{}
+	for _, c := range t.objs[id].Class {
+		if codes[c.Code] && !seen[c.Code] {
+			seen[c.Code] = true
+			headers = append(headers, t.dim(id, c.Code))
+		}
+	}
+
+	// CLASS_INF にないコード (Resample で作ったものなど) はレコードのメタ情報を使い、出現順に並べる
+	for _, r := range t.Records {
+		d := r.Dims[di]
+		if codes[d.Code] && !seen[d.Code] {
+			seen[d.Code] = true
+			headers = append(headers, d)
+		}
+	}
+
+	return headers
+}
+
+func pivotLabel(d TableDim) string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return d.Code
+}
+
+func pivotValue(n Number) string {
+	if n.Valid {
+		return strconv.FormatFloat(n.Float, 'f', -1, 64)
+	}
+	return n.Char
+}
+
+// 見出しの行と列を含む表を返す。
+func (p *PivotTable) records() [][]string {
+	header := make([]string, len(p.Columns)+1)
+	if len(p.Rows) > 0 {
+		header[0] = p.Rows[0].ObjName
+	}
+	for j, column := range p.Columns {
+		header[j+1] = pivotLabel(column)
+	}
+
+	records := [][]string{header}
+	for i, row := range p.Rows {
+		record := make([]string, len(p.Columns)+1)
+		record[0] = pivotLabel(row)
+		for j, n := range p.Cells[i] {
+			record[j+1] = pivotValue(n)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// CSV 形式で書き出します。
+func (p *PivotTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(p.records()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// Markdown の表として書き出します。
+func (p *PivotTable) WriteMarkdown(w io.Writer) error {
+	for i, record := range p.records() {
+		cells := make([]string, len(record))
+		for j, s := range record {
+			cells[j] = markdownEscaper.Replace(s)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+
+		if i == 0 {
+			separators := make([]string, len(record))
+			separators[0] = "---"
+			for j := 1; j < len(separators); j++ {
+				separators[j] = "---:"
+			}
+			if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(separators, " | ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
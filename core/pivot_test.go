@@ -0,0 +1,166 @@
+package core_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func newPivotTable(duplicates ...core.DataInfValue) *core.Table {
+	return core.NewTable(core.ResponseGetStatsDataStatisticalData{
+		Class: core.ClassInf{
+			ClassObj: []core.ClassObj{
+				{ID: "tab", Name: "表章項目", Class: []core.ClassObjClass{{Code: "01", Name: "人口"}, {Code: "02", Name: "世帯数"}}},
+				{ID: "cat01", Name: "男女", Class: []core.ClassObjClass{{Code: "1", Name: "男"}, {Code: "2", Name: "女"}}},
+				{ID: "area", Name: "地域", Class: []core.ClassObjClass{{Code: "00000", Name: "全国"}, {Code: "13000", Name: "東京|都"}}},
+				{ID: "time", Name: "時間軸", Class: []core.ClassObjClass{{Code: "2020000000", Name: "2020年"}, {Code: "2015000000", Name: "2015年"}}},
+			},
+		},
+		Data: core.DataInf{
+			Note: []core.DataInfNote{{Char: "x", Note: "秘匿"}},
+			Value: append([]core.DataInfValue{
+				{Tab: "01", Cat01: "1", Area: "13000", Time: "2015000000", Value: "3"},
+				{Tab: "01", Cat01: "2", Area: "13000", Time: "2015000000", Value: "4"},
+				{Tab: "01", Cat01: "1", Area: "00000", Time: "2015000000", Value: "10"},
+				{Tab: "01", Cat01: "2", Area: "00000", Time: "2015000000", Value: "11"},
+				{Tab: "01", Cat01: "1", Area: "00000", Time: "2020000000", Value: "9.5"},
+				{Tab: "01", Cat01: "2", Area: "00000", Time: "2020000000", Value: "x"},
+				{Tab: "02", Cat01: "1", Area: "00000", Time: "2020000000", Value: "100"},
+			}, duplicates...),
+		},
+	})
+}
+
+func TestPivot(t *testing.T) {
+	p, err := newPivotTable().Pivot(core.PivotOptions{
+		Rows:    "area",
+		Columns: "time",
+		Filters: map[string]string{"tab": "01", "cat01": "2"},
+	})
+	if err != nil {
+		t.Fatalf("Pivot() error = %v", err)
+	}
+
+	var csv strings.Builder
+	if err := p.WriteCSV(&csv); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	wantCSV := "地域,2020年,2015年\n全国,x,11\n東京|都,,4\n"
+	if csv.String() != wantCSV {
+		t.Errorf("WriteCSV() = %q, want %q", csv.String(), wantCSV)
+	}
+
+	var md strings.Builder
+	if err := p.WriteMarkdown(&md); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	wantMarkdown := "| 地域 | 2020年 | 2015年 |\n| --- | ---: | ---: |\n| 全国 | x | 11 |\n| 東京\\|都 |  | 4 |\n"
+	if md.String() != wantMarkdown {
+		t.Errorf("WriteMarkdown() = %q, want %q", md.String(), wantMarkdown)
+	}
+
+	if n := p.Cells[0][0]; n.Valid || n.Reason != "秘匿" {
+		t.Errorf("Cells[0][0] = %+v", n)
+	}
+}
+
+func TestPivotResampled(t *testing.T) {
+	var values []core.DataInfValue
+	for i, code := range []string{"2022000103", "2022000406", "2022000709", "2022001012"} {
+		values = append(values,
+			core.DataInfValue{Area: "00000", Time: code, Value: strconv.Itoa(i + 1)},
+			core.DataInfValue{Area: "13000", Time: code, Value: "1"},
+		)
+	}
+	table := core.NewTable(core.ResponseGetStatsDataStatisticalData{
+		Class: core.ClassInf{
+			ClassObj: []core.ClassObj{
+				{ID: "area", Name: "地域", Class: []core.ClassObjClass{{Code: "00000", Name: "全国"}, {Code: "13000", Name: "東京都"}}},
+				{ID: "time", Name: "時間軸", Class: []core.ClassObjClass{{Code: "2022000103", Name: "2022年1-3月期"}}},
+			},
+		},
+		Data: core.DataInf{Value: values},
+	})
+
+	// 年にまとめたコードは CLASS_INF にないため、Resample で付けた名前を使う
+	resampled := table.Resample(core.GranularityYear, false, core.AggregateSum, core.ReplaceSpCharNone)
+	p, err := resampled.Pivot(core.PivotOptions{Rows: "area", Columns: "time"})
+	if err != nil {
+		t.Fatalf("Pivot() error = %v", err)
+	}
+
+	var csv strings.Builder
+	if err := p.WriteCSV(&csv); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	wantCSV := "地域,2022\n全国,10\n東京都,4\n"
+	if csv.String() != wantCSV {
+		t.Errorf("WriteCSV() = %q, want %q", csv.String(), wantCSV)
+	}
+	if got := p.Columns[0]; got.ObjName != "時間軸" || got.Name != "2022" {
+		t.Errorf("Columns[0] = %+v", got)
+	}
+}
+
+func TestPivotAggregation(t *testing.T) {
+	table := newPivotTable(core.DataInfValue{Tab: "01", Cat01: "1", Area: "00000", Time: "2015000000", Value: "20"})
+
+	tests := []struct {
+		name        string
+		aggregation core.Aggregation
+		want        float64
+	}{
+		{name: "Sum", want: 30},
+		{name: "Mean", aggregation: core.AggregateMean, want: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := table.Pivot(core.PivotOptions{
+				Rows:        "cat01",
+				Columns:     "area",
+				Filters:     map[string]string{"tab": "01", "time": "2015000000"},
+				Aggregation: tt.aggregation,
+			})
+			if err != nil {
+				t.Fatalf("Pivot() error = %v", err)
+			}
+			if p.Cells[0][0].Float != tt.want || p.Cells[1][1].Float != 4 {
+				t.Errorf("Cells = %v", p.Cells)
+			}
+		})
+	}
+}
+
+func TestPivotError(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    core.PivotOptions
+		wantErr string
+	}{
+		{name: "SameDimension", opts: core.PivotOptions{Rows: "area", Columns: "area"}},
+		{name: "UnknownRows", opts: core.PivotOptions{Rows: "cat02", Columns: "area"}},
+		{name: "UnknownFilter", opts: core.PivotOptions{Rows: "area", Columns: "time", Filters: map[string]string{"cat09": "1"}}},
+		{
+			name:    "UnfixedCategory",
+			opts:    core.PivotOptions{Rows: "area", Columns: "time", Filters: map[string]string{"tab": "01"}},
+			wantErr: "cat01",
+		},
+		{
+			name:    "UnfixedTab",
+			opts:    core.PivotOptions{Rows: "area", Columns: "time", Filters: map[string]string{"cat01": "1"}},
+			wantErr: "tab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPivotTable().Pivot(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Pivot() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
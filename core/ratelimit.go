@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// トークンバケットによるリクエスト数の制限と、同時実行数の制限を行います。
+//
+// 同じ RateLimiter を複数の HttpClient に渡すことで、制限を共有できます。
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	inFlight chan struct{}
+
+	stats RateLimiterStats
+}
+
+// RateLimiter の待機時間の統計
+type RateLimiterStats struct {
+	// 許可したリクエスト数
+	Requests int64
+
+	// 待機が発生したリクエスト数
+	Waited int64
+
+	// 待機時間の合計
+	TotalWait time.Duration
+
+	// 待機時間の最大値
+	MaxWait time.Duration
+}
+
+// 1 秒あたり requestsPerSecond 回、最大 burst 回まで連続してリクエストを許可し、
+// 同時に maxInFlight 件までリクエストを実行する RateLimiter を返します。
+//
+// requestsPerSecond、maxInFlight が 0 以下の場合はそれぞれ制限しません。
+func NewRateLimiter(requestsPerSecond float64, burst int, maxInFlight int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l := &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// リクエスト数の制限を指定します。
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *HttpClient) {
+		c.rateLimiter = limiter
+	}
+}
+
+// リクエストの実行を許可されるまで待機します。
+//
+// 許可された場合はリクエストの完了後に呼び出す関数を返します。
+func (l *RateLimiter) Wait(ctx context.Context) (release func(), err error) {
+	start := time.Now()
+	waited := false
+
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		default:
+			waited = true
+			select {
+			case l.inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	release = l.releaseFunc()
+
+	if delay := l.reserve(time.Now()); delay > 0 {
+		waited = true
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.cancelReservation()
+			release()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	l.record(waited, time.Since(start))
+	return release, nil
+}
+
+// 待機時間の統計を返します。
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+func (l *RateLimiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if l.inFlight != nil {
+				<-l.inFlight
+			}
+		})
+	}
+}
+
+// トークンを 1 つ予約し、使えるようになるまでの待機時間を返す。
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+func (l *RateLimiter) cancelReservation() {
+	if l.rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+func (l *RateLimiter) record(waited bool, wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.Requests++
+	if waited {
+		l.stats.Waited++
+		l.stats.TotalWait += wait
+		if wait > l.stats.MaxWait {
+			l.stats.MaxWait = wait
+		}
+	}
+}
+
+// Close でリクエストの完了を RateLimiter に伝える io.ReadCloser
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,99 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func TestRateLimiterRate(t *testing.T) {
+	l := core.NewRateLimiter(50, 1, 0)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, err := l.Wait(ctx)
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		release()
+	}
+
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("Wait() elapsed = %v, want >= %v", elapsed, 70*time.Millisecond)
+	}
+
+	// タイマーが遅れると次のトークンが待たずに使えることがあるため、待機の回数は 3 回以上とする。
+	// 4 件分のトークン (80ms) のほとんどは待機時間になる
+	stats := l.Stats()
+	if stats.Requests != 5 || stats.Waited < 3 || stats.TotalWait < 60*time.Millisecond || stats.MaxWait <= 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	l := core.NewRateLimiter(1, 1, 0)
+
+	release, err := l.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiterMaxInFlight(t *testing.T) {
+	var current, max int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write(responseGetStatsList)
+	}))
+	defer srv.Close()
+
+	l := core.NewRateLimiter(0, 1, 2)
+	clients := []core.IApiClient{
+		core.NewApiClient(core.New(core.WithBaseURL(srv.URL), core.WithRateLimiter(l)), core.CommonParams{}),
+		core.NewApiClient(core.New(core.WithBaseURL(srv.URL), core.WithRateLimiter(l)), core.CommonParams{}),
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(ac core.IApiClient) {
+			defer wg.Done()
+			if _, err := ac.GetStatsList(ctx, core.ParamsGetStatsList{}); err != nil {
+				t.Errorf("GetStatsList() error = %v", err)
+			}
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&max); got > 2 {
+		t.Errorf("max in flight = %d, want <= %d", got, 2)
+	}
+	if stats := l.Stats(); stats.Requests != 6 || stats.Waited == 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
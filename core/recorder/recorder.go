@@ -0,0 +1,259 @@
+// e-Stat API へのリクエストとレスポンスをカセットファイルに記録し、再生する IHttpClient です。
+//
+// 記録したカセットを使うと、実際の e-Stat のレスポンスでオフラインのテストを書けます。
+//
+//	// 記録
+//	rec := recorder.Record("testdata/meta_info.json", core.New())
+//	ac := core.NewApiClient(rec, core.CommonParams{AppID: appID})
+//
+//	// 再生
+//	rec, err := recorder.Replay("testdata/meta_info.json")
+//	ac := core.NewApiClient(rec, core.CommonParams{})
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	querystring "github.com/google/go-querystring/query"
+	"github.com/itok01/e-stat-go/core"
+)
+
+// appId を置き換える文字列
+const Redacted = "REDACTED"
+
+// 記録か再生か
+type Mode int
+
+const (
+	// カセットから再生する
+	ModeReplay Mode = iota
+
+	// リクエストしてカセットに記録する
+	ModeRecord
+)
+
+// 1 回のリクエストとレスポンス
+type Interaction struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	// appId を除き、キーの順に並べたクエリ (POST の場合はフォームの値)
+	Query string `json:"query"`
+
+	// PostJsonWithQuery の JSON のボディ
+	Body json.RawMessage `json:"body,omitempty"`
+
+	Response Response `json:"response"`
+}
+
+// 記録したレスポンス
+type Response struct {
+	StatusCode int    `json:"status"`
+	Body       string `json:"body"`
+}
+
+// カセットファイルの内容
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// カセットにないリクエストのエラー
+type UnmatchedError struct {
+	Cassette string
+	Method   string
+	Path     string
+	Query    string
+	Body     string
+}
+
+func (e *UnmatchedError) Error() string {
+	msg := fmt.Sprintf("recorder: no interaction in %s for %s %s?%s", e.Cassette, e.Method, e.Path, e.Query)
+	if e.Body != "" {
+		msg += " body " + e.Body
+	}
+	return msg
+}
+
+// カセットを記録、再生する IHttpClient
+type Recorder struct {
+	path   string
+	mode   Mode
+	client core.IHttpClient
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// mode に応じて Record または Replay の Recorder を返します。
+func New(path string, mode Mode, client core.IHttpClient) (*Recorder, error) {
+	if mode == ModeRecord {
+		return Record(path, client), nil
+	}
+	return Replay(path)
+}
+
+// client でリクエストし、path のカセットに記録する Recorder を返します。
+//
+// カセットはリクエストごとに書き出します。既存のカセットは上書きされます。
+func Record(path string, client core.IHttpClient) *Recorder {
+	return &Recorder{path: path, mode: ModeRecord, client: client}
+}
+
+// path のカセットから再生する Recorder を返します。
+//
+// カセットにないリクエストには *UnmatchedError を返します。
+func Replay(path string) (*Recorder, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{path: path, mode: ModeReplay}
+	if err := json.Unmarshal(b, &r.cassette); err != nil {
+		return nil, fmt.Errorf("recorder: %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// 記録か再生かを返します。
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// 再生で使われなかったやり取りを返します。
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) Get(ctx context.Context, path string, query any) (int, []byte, error) {
+	return r.do(http.MethodGet, path, query, nil, func() (int, []byte, error) {
+		return r.client.Get(ctx, path, query)
+	})
+}
+
+func (r *Recorder) Post(ctx context.Context, path string, data any) (int, []byte, error) {
+	return r.do(http.MethodPost, path, data, nil, func() (int, []byte, error) {
+		return r.client.Post(ctx, path, data)
+	})
+}
+
+func (r *Recorder) PostJsonWithQuery(ctx context.Context, path string, query any, structuredData any) (int, []byte, error) {
+	body, err := json.Marshal(structuredData)
+	if err != nil {
+		return 0, nil, err
+	}
+	return r.do(http.MethodPost, path, query, body, func() (int, []byte, error) {
+		return r.client.PostJsonWithQuery(ctx, path, query, structuredData)
+	})
+}
+
+func (r *Recorder) do(method string, path string, query any, body []byte, fetch func() (int, []byte, error)) (int, []byte, error) {
+	q, err := core.CanonicalQuery(query)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(method, path, q, body)
+	}
+
+	statusCode, b, err := fetch()
+	if err != nil {
+		return statusCode, b, err
+	}
+
+	respBody := string(b)
+	if appID := appIDOf(query); appID != "" {
+		respBody = strings.ReplaceAll(respBody, appID, Redacted)
+	}
+	if err := r.record(Interaction{
+		Method:   method,
+		Path:     path,
+		Query:    q,
+		Body:     body,
+		Response: Response{StatusCode: statusCode, Body: respBody},
+	}); err != nil {
+		return statusCode, b, err
+	}
+	return statusCode, b, nil
+}
+
+// 同じリクエストが複数ある場合は記録した順に返し、すべて使った後は最後のものを返す。
+func (r *Recorder) replay(method string, path string, query string, body []byte) (int, []byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, in := range r.cassette.Interactions {
+		if in.Method != method || in.Path != path || in.Query != query || !equalJSON(in.Body, body) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return in.Response.StatusCode, []byte(in.Response.Body), nil
+		}
+		last = i
+	}
+	if last >= 0 {
+		in := r.cassette.Interactions[last]
+		return in.Response.StatusCode, []byte(in.Response.Body), nil
+	}
+
+	return 0, nil, &UnmatchedError{Cassette: r.path, Method: method, Path: path, Query: query, Body: string(body)}
+}
+
+func (r *Recorder) record(in Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.used = append(r.used, true)
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, b, 0o644)
+}
+
+func appIDOf(query any) string {
+	values, err := querystring.Values(query)
+	if err != nil {
+		return ""
+	}
+	return values.Get("appId")
+}
+
+// JSON の空白の違いを無視して比較する。
+func equalJSON(a, b []byte) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(x, y)
+}
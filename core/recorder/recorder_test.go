@@ -0,0 +1,135 @@
+package recorder_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+	"github.com/itok01/e-stat-go/core/recorder"
+)
+
+const appID = "secret-app-id"
+
+// パスに応じたレスポンスを返し、リクエストの回数を数える IHttpClient。do を estattest.ClientFunc として使う
+type fakeHttpClient struct {
+	calls int
+}
+
+func (hc *fakeHttpClient) do(ctx context.Context, r estattest.ClientRequest) (int, []byte, error) {
+	hc.calls++
+	switch r.Path {
+	case "/getMetaInfo":
+		return http.StatusOK, []byte(`<GET_META_INFO><RESULT><STATUS>0</STATUS><ERROR_MSG>appId ` + appID + `</ERROR_MSG></RESULT><METADATA_INF><TABLE_INF id="0003109741"></TABLE_INF></METADATA_INF></GET_META_INFO>`), nil
+	case "/getStatsDatas":
+		return http.StatusOK, []byte(`<GET_STATS_DATAS><RESULT><STATUS>0</STATUS></RESULT></GET_STATS_DATAS>`), nil
+	case "/postDataset":
+		return http.StatusOK, []byte(`<POST_DATASET><RESULT><STATUS>0</STATUS></RESULT></POST_DATASET>`), nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func requests(ctx context.Context, ac core.IApiClient) error {
+	meta, err := ac.GetMetaInfoList(ctx, core.ParamsGetMetaInfoList{StatsDataId: "0003109741"})
+	if err != nil {
+		return err
+	}
+	if meta.DataList.Table.ID != "0003109741" {
+		return errors.New("unexpected TABLE_INF id " + meta.DataList.Table.ID)
+	}
+	if _, err := ac.GetStatsDatas(ctx, core.ParamsGetStatsDatas{}, []core.StatsDatasSpec{{StatsDataId: "0003109741"}, {StatsDataId: "0003109742"}}); err != nil {
+		return err
+	}
+	if _, err := ac.PostDataset(ctx, core.ParamsPostDataset{StatsDataId: "0003109741", ProcessMode: core.ProcessModeRegister}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	hc := &fakeHttpClient{}
+	rec := recorder.Record(path, estattest.ClientFunc(hc.do))
+	if err := requests(ctx, core.NewApiClient(rec, core.CommonParams{AppID: appID})); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if hc.calls != 3 {
+		t.Errorf("calls = %d, want 3", hc.calls)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), appID) {
+		t.Errorf("cassette contains appId:\n%s", b)
+	}
+	if !strings.Contains(string(b), recorder.Redacted) {
+		t.Errorf("cassette does not contain %q", recorder.Redacted)
+	}
+
+	rep, err := recorder.Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac := core.NewApiClient(rep, core.CommonParams{AppID: "another"})
+	if err := requests(ctx, ac); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if unused := rep.Unused(); len(unused) != 0 {
+		t.Errorf("Unused() = %v", unused)
+	}
+
+	// 同じリクエストは繰り返し再生できる
+	if err := requests(ctx, ac); err != nil {
+		t.Fatalf("replay again: %v", err)
+	}
+	if hc.calls != 3 {
+		t.Errorf("calls = %d, want 3", hc.calls)
+	}
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec := recorder.Record(path, estattest.ClientFunc((&fakeHttpClient{}).do))
+	if err := requests(ctx, core.NewApiClient(rec, core.CommonParams{AppID: appID})); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := recorder.Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac := core.NewApiClient(rep, core.CommonParams{})
+
+	_, err = ac.GetMetaInfoList(ctx, core.ParamsGetMetaInfoList{StatsDataId: "0000000000"})
+	var unmatched *recorder.UnmatchedError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("GetMetaInfoList() error = %v, want UnmatchedError", err)
+	}
+	if unmatched.Path != "/getMetaInfo" || unmatched.Query != "statsDataId=0000000000" {
+		t.Errorf("UnmatchedError = %+v", unmatched)
+	}
+
+	_, err = ac.GetStatsDatas(ctx, core.ParamsGetStatsDatas{}, []core.StatsDatasSpec{{StatsDataId: "0003109741"}})
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("GetStatsDatas() error = %v, want UnmatchedError", err)
+	}
+
+	if got := len(rep.Unused()); got != 3 {
+		t.Errorf("len(Unused()) = %d, want 3", got)
+	}
+
+	if _, err := recorder.Replay(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Replay() missing cassette error = nil")
+	}
+}
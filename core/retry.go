@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// リトライの方針
+type RetryPolicy struct {
+	// 最初のリクエストを含めた最大試行回数。1 以下の場合はリトライしません。
+	MaxAttempts int
+
+	// 1 回目のリトライまでの待機時間。以降は 2 倍ずつ増えます。
+	BaseDelay time.Duration
+
+	// 待機時間の上限。Retry-After による待機時間には適用されません。
+	MaxDelay time.Duration
+
+	// 待機時間を揺らす割合 (0〜1)。
+	// 0.2 の場合、待機時間は 80%〜100% の間でランダムに決まります。
+	Jitter float64
+
+	// リトライする HTTP ステータスコード。nil の場合は DefaultRetryableStatus を使います。
+	RetryableStatus []int
+
+	// POST によるデータセット登録など、冪等でないリクエストもリトライします。
+	RetryNonIdempotent bool
+}
+
+// リトライする HTTP ステータスコードの既定値
+var DefaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// リトライの方針の既定値
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.2,
+}
+
+// リトライの方針を指定します。
+//
+// 既定ではリトライしません。
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *HttpClient) {
+		c.retryPolicy = &policy
+	}
+}
+
+func (p *RetryPolicy) attempts(idempotent bool) int {
+	if p == nil || p.MaxAttempts < 1 || (!idempotent && !p.RetryNonIdempotent) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	retryableStatus := p.RetryableStatus
+	if retryableStatus == nil {
+		retryableStatus = DefaultRetryableStatus
+	}
+	for _, status := range retryableStatus {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+// attempt 回目の試行に失敗した後の待機時間を返す。
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * p.Jitter * rand.Float64())
+	}
+	return d
+}
+
+// Retry-After の秒数または HTTP 日付を待機時間に変換する。
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// リクエスト数の制限に従ってリクエストを送信する。
+func (c *HttpClient) do(req *http.Request) (*http.Response, error) {
+	if c.rateLimiter == nil {
+		return c.httpClient.Do(req)
+	}
+
+	release, err := c.rateLimiter.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// リトライの方針に従ってリクエストを送信し、リトライした回数とともにレスポンスを返す。
+func (c *HttpClient) doWithRetry(req *http.Request, endpoint string, idempotent bool) (*http.Response, int, error) {
+	ctx := req.Context()
+	attempts := c.retryPolicy.attempts(idempotent)
+
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attempt - 1, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := c.do(r)
+		if attempt >= attempts || !c.retryPolicy.shouldRetry(resp, err) {
+			return resp, attempt - 1, err
+		}
+
+		delay := c.retryPolicy.delay(attempt, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, attempt - 1, err
+		}
+		c.logRetry(req, endpoint, attempt, delay, resp, redactURLError(err))
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt - 1, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
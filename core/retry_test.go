@@ -0,0 +1,115 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		retryAfter   string
+		policy       core.RetryPolicy
+		post         bool
+		timeout      time.Duration
+		wantAttempts int32
+		wantErr      error
+	}{
+		{
+			name:         "RetryUntilSuccess",
+			failures:     2,
+			policy:       core.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			wantAttempts: 3,
+		},
+		{
+			name:         "GiveUp",
+			failures:     5,
+			policy:       core.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			wantAttempts: 3,
+			wantErr:      core.ErrHTTPStatus,
+		},
+		{
+			name:         "RetryAfter",
+			failures:     1,
+			retryAfter:   "0",
+			policy:       core.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour},
+			wantAttempts: 2,
+		},
+		{
+			name:         "DeadlineExceeded",
+			failures:     1,
+			policy:       core.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour},
+			timeout:      time.Second,
+			wantAttempts: 1,
+			wantErr:      core.ErrHTTPStatus,
+		},
+		{
+			name:         "NonIdempotent",
+			failures:     1,
+			policy:       core.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			post:         true,
+			wantAttempts: 1,
+			wantErr:      core.ErrHTTPStatus,
+		},
+		{
+			name:         "NonIdempotentOptIn",
+			failures:     1,
+			policy:       core.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryNonIdempotent: true},
+			post:         true,
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				if r.Method == http.MethodPost {
+					w.Write(responsePostDataset)
+				} else {
+					w.Write(responseGetStatsList)
+				}
+			}))
+			defer srv.Close()
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			hc := core.New(core.WithBaseURL(srv.URL), core.WithRetryPolicy(tt.policy))
+			ac := core.NewApiClient(hc, core.CommonParams{})
+
+			var err error
+			if tt.post {
+				_, err = ac.PostDataset(ctx, core.ParamsPostDataset{})
+			} else {
+				_, err = ac.GetStatsList(ctx, core.ParamsGetStatsList{})
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 集計の検証の設定
+type RollupOptions struct {
+	// 検証する分類事項の ID。nil の場合は階層を持つすべての分類事項を検証します。
+	Dims []string
+
+	// 許容する差の絶対値
+	Tolerance float64
+
+	// 許容する差の親の値に対する割合
+	RelTolerance float64
+
+	// リクエストの ReplaceSpChar
+	ReplaceSpChar int
+}
+
+func (o RollupOptions) tolerance(want float64) float64 {
+	return math.Max(o.Tolerance, o.RelTolerance*math.Abs(want))
+}
+
+// 検証結果の種類
+type RollupFindingKind int
+
+const (
+	// 子の合計が親の値と一致しない
+	RollupMismatch RollupFindingKind = iota
+
+	// 子の値に欠損があり検証できない
+	RollupIncomplete
+)
+
+func (k RollupFindingKind) String() string {
+	switch k {
+	case RollupMismatch:
+		return "mismatch"
+	case RollupIncomplete:
+		return "incomplete"
+	}
+	return "unknown"
+}
+
+// 集計の子の値
+type RollupChild struct {
+	TableDim
+
+	// 値がない場合は Number.Valid と Number.Char がともにゼロ値です。
+	Number Number
+}
+
+// 集計の検証結果
+type RollupFinding struct {
+	Kind RollupFindingKind
+
+	// 検証した分類事項の ID
+	Dim    string
+	Parent TableDim
+
+	// 検証した分類事項以外の分類事項
+	Fixed []TableDim
+
+	// 親の値と子の合計
+	Want Number
+	Sum  float64
+
+	Children []RollupChild
+}
+
+func (f RollupFinding) String() string {
+	fixed := make([]string, len(f.Fixed))
+	for i, d := range f.Fixed {
+		fixed[i] = d.ID + "=" + d.Code
+	}
+
+	var detail string
+	switch f.Kind {
+	case RollupMismatch:
+		detail = fmt.Sprintf("%v != sum %v", f.Want.Float, f.Sum)
+	case RollupIncomplete:
+		var missing []string
+		for _, c := range f.Children {
+			if c.Number.Valid {
+				continue
+			}
+			s := c.Code
+			if c.Number.Char != "" {
+				s += " " + strconv.Quote(c.Number.Char)
+			}
+			if c.Number.Reason != "" {
+				s += " (" + c.Number.Reason + ")"
+			}
+			missing = append(missing, s)
+		}
+		detail = "missing " + strings.Join(missing, ", ")
+	}
+
+	return fmt.Sprintf("%s %s %s (%s) [%s]: %s", f.Kind, f.Dim, f.Parent.Code, f.Parent.Name, strings.Join(fixed, " "), detail)
+}
+
+// 検証する分類事項以外が同じ値の集まり
+type rollupGroup struct {
+	fixed   []TableDim
+	unit    string
+	numbers map[string]Number
+}
+
+// di 番目の分類事項以外でレコードをまとめる。
+func (t *Table) rollupGroups(di int, numbers []Number) []*rollupGroup {
+	var groups []*rollupGroup
+	index := map[string]*rollupGroup{}
+
+	for i, r := range t.Records {
+		fixed := make([]TableDim, 0, len(r.Dims)-1)
+		keys := make([]string, 0, len(r.Dims)-1)
+		for n, d := range r.Dims {
+			if n != di {
+				fixed = append(fixed, d)
+				keys = append(keys, d.Code)
+			}
+		}
+		key := strings.Join(keys, "\x00")
+
+		g, ok := index[key]
+		if !ok {
+			g = &rollupGroup{fixed: fixed, unit: r.Unit, numbers: map[string]Number{}}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.numbers[r.Dims[di].Code] = numbers[i]
+	}
+
+	return groups
+}
+
+func (t *Table) rollupDims(opts RollupOptions) []string {
+	if opts.Dims != nil {
+		return opts.Dims
+	}
+	return t.dims
+}
+
+// 子を持つノードを子から先にたどる。
+func walkRollupParents(tree *ClassTree, f func(*ClassNode)) {
+	var walk func(n *ClassNode)
+	walk = func(n *ClassNode) {
+		for _, c := range n.Children {
+			walk(c)
+		}
+		if len(n.Children) > 0 {
+			f(n)
+		}
+	}
+	for _, root := range tree.Roots {
+		walk(root)
+	}
+}
+
+// 各分類事項の階層について、子の合計が親の値と一致するかを検証します。
+//
+// 親または子がひとつも表にない組み合わせ (子を絞り込んだ表など) は検証しません。
+// 子が親の内訳の一部 (「うち」など) の場合は一致しないため、opts.Dims で対象を指定して下さい。
+func (t *Table) CheckRollup(opts RollupOptions) []RollupFinding {
+	numbers := t.Numbers(opts.ReplaceSpChar)
+
+	var findings []RollupFinding
+	for _, id := range t.rollupDims(opts) {
+		di := t.dimIndex(id)
+		if di < 0 {
+			continue
+		}
+		tree := t.objs[id].Tree()
+
+		for _, g := range t.rollupGroups(di, numbers) {
+			walkRollupParents(tree, func(parent *ClassNode) {
+				want, ok := g.numbers[parent.Code]
+				if !ok || !want.Valid || !hasRollupChild(parent, g.numbers) {
+					return
+				}
+
+				f := RollupFinding{
+					Kind:   RollupMismatch,
+					Dim:    id,
+					Parent: t.dim(id, parent.Code),
+					Fixed:  g.fixed,
+					Want:   want,
+				}
+				for _, c := range parent.Children {
+					n := g.numbers[c.Code]
+					f.Children = append(f.Children, RollupChild{TableDim: t.dim(id, c.Code), Number: n})
+					if n.Valid {
+						f.Sum += n.Float
+					} else {
+						f.Kind = RollupIncomplete
+					}
+				}
+
+				if f.Kind == RollupIncomplete || math.Abs(want.Float-f.Sum) > opts.tolerance(want.Float) {
+					findings = append(findings, f)
+				}
+			})
+		}
+	}
+
+	return findings
+}
+
+// parent の子のうち、表にあるものがあるかを返す。
+func hasRollupChild(parent *ClassNode, numbers map[string]Number) bool {
+	for _, c := range parent.Children {
+		if _, ok := numbers[c.Code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// 親の値がなく、子の値がすべてある場合に子の合計から親の値を計算したレコードを返します。
+//
+// 計算した親の値は、さらに上の階層の計算に使います。
+func (t *Table) Rollup(opts RollupOptions) []TableRecord {
+	numbers := t.Numbers(opts.ReplaceSpChar)
+
+	var records []TableRecord
+	for _, id := range t.rollupDims(opts) {
+		di := t.dimIndex(id)
+		if di < 0 {
+			continue
+		}
+		tree := t.objs[id].Tree()
+
+		for _, g := range t.rollupGroups(di, numbers) {
+			walkRollupParents(tree, func(parent *ClassNode) {
+				if n, ok := g.numbers[parent.Code]; ok && n.Valid {
+					return
+				}
+
+				var sum float64
+				for _, c := range parent.Children {
+					n := g.numbers[c.Code]
+					if !n.Valid {
+						return
+					}
+					sum += n.Float
+				}
+				g.numbers[parent.Code] = Number{Valid: true, Float: sum, Unit: g.unit}
+
+				r := TableRecord{
+					Dims:  make([]TableDim, 0, len(t.dims)),
+					Unit:  g.unit,
+					Value: strconv.FormatFloat(sum, 'f', -1, 64),
+				}
+				r.Dims = append(r.Dims, g.fixed[:di]...)
+				r.Dims = append(r.Dims, t.dim(id, parent.Code))
+				r.Dims = append(r.Dims, g.fixed[di:]...)
+				records = append(records, r)
+			})
+		}
+	}
+
+	return records
+}
@@ -0,0 +1,117 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func newRollupTable() *core.Table {
+	return core.NewTable(core.ResponseGetStatsDataStatisticalData{
+		Class: core.ClassInf{
+			ClassObj: []core.ClassObj{
+				{
+					ID:   "area",
+					Name: "地域",
+					Class: []core.ClassObjClass{
+						{Code: "00000", Name: "全国", Level: "1"},
+						{Code: "13000", Name: "東京都", Level: "2", ParentCode: "00000"},
+						{Code: "27000", Name: "大阪府", Level: "2", ParentCode: "00000"},
+					},
+				},
+				{
+					ID:   "time",
+					Name: "時間軸",
+					Class: []core.ClassObjClass{
+						{Code: "2020000000", Name: "2020年"},
+						{Code: "2021000000", Name: "2021年"},
+						{Code: "2022000000", Name: "2022年"},
+						{Code: "2023000000", Name: "2023年"},
+					},
+				},
+			},
+		},
+		Data: core.DataInf{
+			Note: []core.DataInfNote{{Char: "x", Note: "秘匿"}},
+			Value: []core.DataInfValue{
+				{Area: "00000", Time: "2020000000", Value: "100"},
+				{Area: "13000", Time: "2020000000", Value: "60"},
+				{Area: "27000", Time: "2020000000", Value: "40.5"},
+				{Area: "00000", Time: "2021000000", Value: "100"},
+				{Area: "13000", Time: "2021000000", Value: "60"},
+				{Area: "27000", Time: "2021000000", Value: "30"},
+				{Area: "00000", Time: "2022000000", Value: "100"},
+				{Area: "13000", Time: "2022000000", Value: "60"},
+				{Area: "27000", Time: "2022000000", Value: "x"},
+				{Area: "13000", Time: "2023000000", Value: "1"},
+				{Area: "27000", Time: "2023000000", Value: "2"},
+			},
+		},
+	})
+}
+
+func TestCheckRollup(t *testing.T) {
+	findings := newRollupTable().CheckRollup(core.RollupOptions{Tolerance: 1})
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"mismatch area 00000 (全国) [time=2021000000]: 100 != sum 90",
+		`incomplete area 00000 (全国) [time=2022000000]: missing 27000 "x" (秘匿)`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckRollup() = %q, want %q", got, want)
+	}
+
+	if f := findings[1]; f.Children[1].Name != "大阪府" || f.Children[1].Number.Reason != "秘匿" || f.Fixed[0].Name != "2022年" {
+		t.Errorf("CheckRollup()[1] = %+v", f)
+	}
+}
+
+func TestCheckRollupNarrowed(t *testing.T) {
+	// 階層 1 に絞り込んだ表には子がないため、親を検証しない
+	table := core.NewTable(core.ResponseGetStatsDataStatisticalData{
+		Class: core.ClassInf{
+			ClassObj: []core.ClassObj{
+				{
+					ID:   "area",
+					Name: "地域",
+					Class: []core.ClassObjClass{
+						{Code: "00000", Name: "全国", Level: "1"},
+						{Code: "13000", Name: "東京都", Level: "2", ParentCode: "00000"},
+						{Code: "27000", Name: "大阪府", Level: "2", ParentCode: "00000"},
+					},
+				},
+			},
+		},
+		Data: core.DataInf{
+			Value: []core.DataInfValue{
+				{Area: "00000", Value: "100"},
+			},
+		},
+	})
+
+	if findings := table.CheckRollup(core.RollupOptions{}); len(findings) != 0 {
+		t.Errorf("CheckRollup() = %v, want none", findings)
+	}
+}
+
+func TestRollup(t *testing.T) {
+	records := newRollupTable().Rollup(core.RollupOptions{Dims: []string{"area"}})
+
+	want := []core.TableRecord{
+		{
+			Dims: []core.TableDim{
+				{ID: "area", ObjName: "地域", Code: "00000", Name: "全国", Level: "1"},
+				{ID: "time", ObjName: "時間軸", Code: "2023000000", Name: "2023年"},
+			},
+			Value: "3",
+		},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Rollup() = %+v, want %+v", records, want)
+	}
+}
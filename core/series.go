@@ -0,0 +1,226 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 時系列の 1 時点の値
+type SeriesPoint struct {
+	Period Period
+	Number Number
+}
+
+// 時間軸以外の分類事項の組み合わせごとの時系列
+type Series struct {
+	// 時間軸以外の分類事項
+	Labels []TableDim
+	Unit   string
+
+	// 期間の順に並んだ値
+	Points []SeriesPoint
+}
+
+// id の分類事項を返します。
+func (s Series) Label(id string) (TableDim, bool) {
+	for _, d := range s.Labels {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return TableDim{}, false
+}
+
+// 分類事項の名前を " / " で連結して返します。
+func (s Series) Name() string {
+	names := make([]string, len(s.Labels))
+	for i, d := range s.Labels {
+		names[i] = d.Name
+		if names[i] == "" {
+			names[i] = d.Code
+		}
+	}
+	return strings.Join(names, " / ")
+}
+
+// 期間のキー。コードが異なっても同じ期間であれば同じキーになる。
+type periodKey struct {
+	start  time.Time
+	months int
+}
+
+func keyOf(p Period) periodKey {
+	return periodKey{start: p.Start, months: p.Months()}
+}
+
+func (s Series) lookup() map[periodKey]Number {
+	m := make(map[periodKey]Number, len(s.Points))
+	for _, p := range s.Points {
+		m[keyOf(p.Period)] = p.Number
+	}
+	return m
+}
+
+// 時間軸以外の分類事項の組み合わせごとに時系列を作ります。
+//
+// 統計データ取得の結果からは NewTable、StatsDataIterator からは ReadTable で Table を作って下さい。
+func (t *Table) Series(replaceSpChar int) ([]Series, error) {
+	ti := t.dimIndex("time")
+	if ti < 0 {
+		return nil, fmt.Errorf("e-stat: table has no time dimension")
+	}
+	numbers := t.Numbers(replaceSpChar)
+
+	var series []*Series
+	index := map[string]*Series{}
+
+	for i, r := range t.Records {
+		p, err := ParsePeriod(r.Dims[ti].Code)
+		if err != nil {
+			return nil, err
+		}
+
+		labels := make([]TableDim, 0, len(r.Dims)-1)
+		keys := make([]string, 0, len(r.Dims)-1)
+		for n, d := range r.Dims {
+			if n != ti {
+				labels = append(labels, d)
+				keys = append(keys, d.Code)
+			}
+		}
+		key := strings.Join(keys, "\x00")
+
+		s, ok := index[key]
+		if !ok {
+			s = &Series{Labels: labels, Unit: r.Unit}
+			index[key] = s
+			series = append(series, s)
+		}
+		s.Points = append(s.Points, SeriesPoint{Period: p, Number: numbers[i]})
+	}
+
+	result := make([]Series, len(series))
+	for i, s := range series {
+		sort.SliceStable(s.Points, func(a, b int) bool {
+			return s.Points[a].Period.Before(s.Points[b].Period)
+		})
+		result[i] = *s
+	}
+	return result, nil
+}
+
+// 値を変換した時系列を返す。base が見つからない点は欠損値になる。
+func (s Series) derive(unit string, base func(Period) (Number, bool), f func(v, base float64) (float64, bool)) Series {
+	derived := Series{
+		Labels: s.Labels,
+		Unit:   unit,
+		Points: make([]SeriesPoint, len(s.Points)),
+	}
+	for i, p := range s.Points {
+		n := Number{Unit: unit}
+		b, ok := base(p.Period)
+		switch {
+		case !p.Number.Valid:
+			n.Char, n.Reason = p.Number.Char, p.Number.Reason
+		case ok && !b.Valid:
+			n.Char, n.Reason = b.Char, b.Reason
+		case ok:
+			n.Float, n.Valid = f(p.Number.Float, b.Float)
+		}
+		derived.Points[i] = SeriesPoint{Period: p.Period, Number: n}
+	}
+	return derived
+}
+
+func percentChange(v, base float64) (float64, bool) {
+	if base == 0 {
+		return 0, false
+	}
+	return (v/base - 1) * 100, true
+}
+
+// 前期比 (%) の時系列を返します。
+//
+// 前期は同じ長さの直前の期間です。前期の値がない点は欠損値になります。
+func (s Series) Growth() Series {
+	values := s.lookup()
+	return s.derive("%", func(p Period) (Number, bool) {
+		n, ok := values[keyOf(p.Add(-1))]
+		return n, ok
+	}, percentChange)
+}
+
+// 前年同期比 (%) の時系列を返します。
+func (s Series) YearOverYear() Series {
+	values := s.lookup()
+	return s.derive("%", func(p Period) (Number, bool) {
+		n, ok := values[periodKey{start: p.Start.AddDate(-1, 0, 0), months: p.Months()}]
+		return n, ok
+	}, percentChange)
+}
+
+// base の期間に含まれる値の平均を 100 とした指数の時系列を返します。
+//
+//	p, _ := core.ParsePeriod("2020000000")
+//	index, err := s.Rebase(p)
+func (s Series) Rebase(base Period) (Series, error) {
+	var sum float64
+	var count int
+	for _, p := range s.Points {
+		if p.Number.Valid && !p.Period.Start.Before(base.Start) && !p.Period.End.After(base.End) {
+			sum += p.Number.Float
+			count++
+		}
+	}
+	if count == 0 || sum == 0 {
+		return Series{}, fmt.Errorf("e-stat: no base value in %s", base)
+	}
+
+	mean := Number{Valid: true, Float: sum / float64(count)}
+	return s.derive("", func(Period) (Number, bool) {
+		return mean, true
+	}, func(v, base float64) (float64, bool) {
+		return v / base * 100, true
+	}), nil
+}
+
+// 複数の時系列を期間でそろえます。
+//
+// すべての期間を順に並べた periods と、series ごとの値を返します。値がない期間は Number のゼロ値です。
+// 期間の長さが異なる時系列はそろえられません。
+func AlignSeries(series ...Series) ([]Period, [][]Number, error) {
+	var periods []Period
+	seen := map[periodKey]bool{}
+	var granularity Granularity
+
+	for _, s := range series {
+		for _, p := range s.Points {
+			if len(periods) == 0 {
+				granularity = p.Period.Granularity
+			}
+			if p.Period.Granularity != granularity {
+				return nil, nil, fmt.Errorf("e-stat: cannot align %s and %s series", granularity, p.Period.Granularity)
+			}
+			if k := keyOf(p.Period); !seen[k] {
+				seen[k] = true
+				periods = append(periods, p.Period)
+			}
+		}
+	}
+	sort.SliceStable(periods, func(i, j int) bool {
+		return periods[i].Before(periods[j])
+	})
+
+	values := make([][]Number, len(series))
+	for i, s := range series {
+		lookup := s.lookup()
+		values[i] = make([]Number, len(periods))
+		for n, p := range periods {
+			values[i][n] = lookup[keyOf(p)]
+		}
+	}
+
+	return periods, values, nil
+}
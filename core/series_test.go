@@ -0,0 +1,128 @@
+package core_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func seriesFloats(s core.Series) []float64 {
+	values := make([]float64, len(s.Points))
+	for i, p := range s.Points {
+		values[i] = math.NaN()
+		if p.Number.Valid {
+			values[i] = math.Round(p.Number.Float*100) / 100
+		}
+	}
+	return values
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !(math.IsNaN(a[i]) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func newSeriesTable() *core.Table {
+	return core.NewTable(core.ResponseGetStatsDataStatisticalData{
+		Class: core.ClassInf{
+			ClassObj: []core.ClassObj{
+				{ID: "tab", Class: []core.ClassObjClass{{Code: "01", Name: "金額", Unit: "円"}}},
+				{ID: "area", Class: []core.ClassObjClass{{Code: "00000", Name: "全国"}, {Code: "13000", Name: "東京都"}}},
+				{ID: "time"},
+			},
+		},
+		Data: core.DataInf{
+			Note: []core.DataInfNote{{Char: "-", Note: "該当なし"}},
+			Value: []core.DataInfValue{
+				{Tab: "01", Area: "00000", Time: "2021000406", Value: "110"},
+				{Tab: "01", Area: "00000", Time: "2021000103", Value: "100"},
+				{Tab: "01", Area: "00000", Time: "2021000709", Value: "-"},
+				{Tab: "01", Area: "00000", Time: "2022000103", Value: "120"},
+				{Tab: "01", Area: "00000", Time: "2022000406", Value: "121"},
+				{Tab: "01", Area: "13000", Time: "2021000103", Value: "50"},
+			},
+		},
+	})
+}
+
+func TestTableSeries(t *testing.T) {
+	series, err := newSeriesTable().Series(core.ReplaceSpCharNone)
+	if err != nil {
+		t.Fatalf("Series() error = %v", err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("len(Series()) = %d, want 2", len(series))
+	}
+
+	s := series[0]
+	if s.Name() != "金額 / 全国" || s.Unit != "円" {
+		t.Errorf("Name() = %s, Unit = %s", s.Name(), s.Unit)
+	}
+	if d, ok := s.Label("area"); !ok || d.Code != "00000" {
+		t.Errorf("Label(area) = %v, %v", d, ok)
+	}
+
+	nan := math.NaN()
+	tests := []struct {
+		name string
+		got  core.Series
+		want []float64
+	}{
+		{name: "Points", got: s, want: []float64{100, 110, nan, 120, 121}},
+		{name: "Growth", got: s.Growth(), want: []float64{nan, 10, nan, nan, 0.83}},
+		{name: "YearOverYear", got: s.YearOverYear(), want: []float64{nan, nan, nan, 20, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := seriesFloats(tt.got); !equalFloats(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if n := s.Growth().Points[2].Number; n.Char != "-" || n.Reason != "該当なし" {
+		t.Errorf("Growth() missing = %+v", n)
+	}
+
+	base, _ := core.ParsePeriod("2021000000")
+	rebased, err := s.Rebase(base)
+	if err != nil {
+		t.Fatalf("Rebase() error = %v", err)
+	}
+	if got := seriesFloats(rebased); !equalFloats(got, []float64{95.24, 104.76, nan, 114.29, 115.24}) {
+		t.Errorf("Rebase() = %v", got)
+	}
+}
+
+func TestAlignSeries(t *testing.T) {
+	series, err := newSeriesTable().Series(core.ReplaceSpCharNone)
+	if err != nil {
+		t.Fatalf("Series() error = %v", err)
+	}
+
+	periods, values, err := core.AlignSeries(series[1], series[0])
+	if err != nil {
+		t.Fatalf("AlignSeries() error = %v", err)
+	}
+	if len(periods) != 5 || periods[0].Code != "2021000103" || periods[4].Code != "2022000406" {
+		t.Errorf("AlignSeries() periods = %v", periods)
+	}
+	if !values[0][0].Valid || values[0][0].Float != 50 || values[0][1].Valid || values[1][1].Float != 110 {
+		t.Errorf("AlignSeries() values = %v", values)
+	}
+
+	year, _ := core.ParsePeriod("2021000000")
+	annual := core.Series{Points: []core.SeriesPoint{{Period: year}}}
+	if _, _, err := core.AlignSeries(series[0], annual); err == nil {
+		t.Errorf("AlignSeries() error = nil, want error")
+	}
+}
@@ -0,0 +1,388 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ParamsGetSimpleStatsData struct {
+	ParamsGetStatsData
+
+	// # セクションヘッダフラグ
+	//
+	// ・1：セクションヘッダを出力する (省略値)
+	//
+	// ・2：セクションヘッダを取得しない
+	SectionHeaderFlg string `url:"sectionHeaderFlg,omitempty" xml:"SECTION_HEADER_FLG"`
+}
+
+// パラメータを検証します。
+func (p ParamsGetSimpleStatsData) Validate() error {
+	var v paramValidator
+	p.ParamsGetStatsData.validate(&v)
+	v.sectionHeaderFlg(p.SectionHeaderFlg)
+	return v.err()
+}
+
+type ParamsGetSimpleStatsDataRoot struct {
+	CommonParams
+	ParamsGetSimpleStatsData
+}
+
+// CSV 形式のセクションヘッダ
+//
+// "RESULT" や "TABLE_INF" などのセクション名と、それに続くレコードを保持します。
+type SimpleStatsDataSection struct {
+	Name    string
+	Records [][]string
+}
+
+// key から始まる最初のレコードの値を返します。
+func (s SimpleStatsDataSection) Lookup(key string) (string, bool) {
+	for _, record := range s.Records {
+		if len(record) > 1 && record[0] == key {
+			return record[1], true
+		}
+	}
+	return "", false
+}
+
+// CSV 形式の統計データ
+type SimpleStatsData struct {
+	Header  []SimpleStatsDataSection
+	Columns []string
+	Rows    [][]string
+}
+
+// name のセクションヘッダを返します。
+func (d *SimpleStatsData) Section(name string) (SimpleStatsDataSection, bool) {
+	for _, section := range d.Header {
+		if section.Name == name {
+			return section, true
+		}
+	}
+	return SimpleStatsDataSection{}, false
+}
+
+// セクションヘッダの RESULT を返します。
+func (d *SimpleStatsData) Result() (ResponseResult, bool) {
+	section, ok := d.Section("RESULT")
+	if !ok {
+		return ResponseResult{}, false
+	}
+
+	var result ResponseResult
+	if status, ok := section.Lookup("STATUS"); ok {
+		result.Status, _ = strconv.Atoi(status)
+	}
+	result.ErrorMsg, _ = section.Lookup("ERROR_MSG")
+	if date, ok := section.Lookup("DATE"); ok {
+		result.Date, _ = time.Parse(time.RFC3339, date)
+	}
+
+	return result, true
+}
+
+// 各行を列名をキーとした map として返します。
+func (d *SimpleStatsData) Records() []map[string]string {
+	records := make([]map[string]string, len(d.Rows))
+	for i, row := range d.Rows {
+		records[i] = rowToMap(d.Columns, row)
+	}
+	return records
+}
+
+// セクションヘッダの名前。1 列だけのデータの行と区別するため、既知の名前だけをセクションとして扱う
+var simpleStatsDataSections = map[string]bool{
+	"RESULT":                true,
+	"PARAMETER":             true,
+	"PARAMETER_LIST":        true,
+	"STATISTICAL_DATA":      true,
+	"STATISTICAL_DATA_LIST": true,
+	"VALUE":                 true,
+}
+
+// CSV 形式の統計データを 1 行ずつ読み込みます。
+//
+// セクションヘッダは行の読み込みに合わせて Header に蓄積されます。
+type SimpleStatsDataReader struct {
+	r           *csv.Reader
+	header      []SimpleStatsDataSection
+	columns     []string
+	needColumns bool
+	inData      bool
+}
+
+func NewSimpleStatsDataReader(r io.Reader) *SimpleStatsDataReader {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+
+	return &SimpleStatsDataReader{r: cr}
+}
+
+// これまでに読み込んだセクションヘッダを返します。
+func (r *SimpleStatsDataReader) Header() []SimpleStatsDataSection {
+	return r.header
+}
+
+// 列名を返します。最初の行を読み込むまでは nil です。
+func (r *SimpleStatsDataReader) Columns() []string {
+	return r.columns
+}
+
+// 次の行を返します。行がなくなると io.EOF を返します。
+func (r *SimpleStatsDataReader) Read() ([]string, error) {
+	for {
+		record, err := r.r.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(record) == 1 && simpleStatsDataSections[record[0]] {
+			r.inData = false
+			if record[0] == "VALUE" {
+				r.needColumns = true
+			} else {
+				r.header = append(r.header, SimpleStatsDataSection{Name: record[0]})
+			}
+			continue
+		}
+
+		// セクションヘッダがない場合は最初のレコードが列名になる
+		if r.needColumns || (r.columns == nil && len(r.header) == 0) {
+			r.columns = record
+			r.needColumns = false
+			r.inData = true
+			continue
+		}
+
+		if r.inData {
+			return record, nil
+		}
+
+		section := &r.header[len(r.header)-1]
+		section.Records = append(section.Records, record)
+	}
+}
+
+// 次の行を列名をキーとした map として返します。
+func (r *SimpleStatsDataReader) ReadMap() (map[string]string, error) {
+	row, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	return rowToMap(r.columns, row), nil
+}
+
+// CSV 形式の統計データをすべて読み込みます。
+func ReadSimpleStatsData(r io.Reader) (*SimpleStatsData, error) {
+	sr := NewSimpleStatsDataReader(r)
+
+	data := &SimpleStatsData{}
+	for {
+		row, err := sr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	data.Header = sr.Header()
+	data.Columns = sr.Columns()
+
+	return data, nil
+}
+
+func rowToMap(columns []string, row []string) map[string]string {
+	m := make(map[string]string, len(columns))
+	for i, column := range columns {
+		if i < len(row) {
+			m[column] = row[i]
+		}
+	}
+	return m
+}
+
+// 統計データ取得 (CSV 形式)
+//
+// https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_6
+func (c *ApiClient) GetSimpleStatsData(ctx context.Context, params ParamsGetSimpleStatsData) (*SimpleStatsData, error) {
+	body, err := c.getSimpleStatsDataStream(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := ReadSimpleStatsData(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if result, ok := data.Result(); ok {
+		return data, c.checkResult("/getSimpleStatsData", http.StatusOK, result)
+	}
+	return data, nil
+}
+
+// 統計データ取得 (CSV 形式) のレスポンスをそのまま返します。
+//
+// 返された io.ReadCloser は呼び出し側で閉じる必要があります。
+// レスポンスの RESULT の STATUS がエラーの場合は *APIError を返し、
+// 警告の場合は *APIError とともに io.ReadCloser を返します。
+func (c *ApiClient) GetSimpleStatsDataReader(ctx context.Context, params ParamsGetSimpleStatsData) (io.ReadCloser, error) {
+	body, err := c.getSimpleStatsDataStream(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return c.checkStreamResult("/getSimpleStatsData", body)
+}
+
+// 統計データ取得 (CSV 形式) のレスポンスを RESULT を確認せずに返す。
+func (c *ApiClient) getSimpleStatsDataStream(ctx context.Context, params ParamsGetSimpleStatsData) (io.ReadCloser, error) {
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	query := ParamsGetSimpleStatsDataRoot{
+		CommonParams:             c.CommonParams,
+		ParamsGetSimpleStatsData: params,
+	}
+
+	if sc, ok := c.HttpClient.(IHttpStreamClient); ok {
+		statusCode, body, err := sc.GetStream(ctx, "/getSimpleStatsData", query)
+		if err != nil {
+			return nil, err
+		}
+		if !isSuccessHTTPStatus(statusCode) {
+			body.Close()
+			return nil, decodeError("/getSimpleStatsData", statusCode, nil)
+		}
+		return body, nil
+	}
+
+	statusCode, body, err := c.HttpClient.Get(ctx, "/getSimpleStatsData", query)
+	if err != nil {
+		return nil, err
+	}
+	if !isSuccessHTTPStatus(statusCode) {
+		return nil, decodeError("/getSimpleStatsData", statusCode, nil)
+	}
+
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+// 統計データ一括取得 (CSV 形式)
+//
+// https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_8
+func (c *ApiClient) GetSimpleStatsDatas(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (*SimpleStatsData, error) {
+	body, err := c.getSimpleStatsDatasStream(ctx, params, statsDatasSpec)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := ReadSimpleStatsData(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if result, ok := data.Result(); ok {
+		return data, c.checkResult("/getSimpleStatsDatas", http.StatusOK, result)
+	}
+	return data, nil
+}
+
+// 統計データ一括取得 (CSV 形式) のレスポンスをそのまま返します。
+//
+// 返された io.ReadCloser は呼び出し側で閉じる必要があります。
+// レスポンスの RESULT の STATUS がエラーの場合は *APIError を返し、
+// 警告の場合は *APIError とともに io.ReadCloser を返します。
+func (c *ApiClient) GetSimpleStatsDatasReader(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (io.ReadCloser, error) {
+	body, err := c.getSimpleStatsDatasStream(ctx, params, statsDatasSpec)
+	if err != nil {
+		return nil, err
+	}
+	return c.checkStreamResult("/getSimpleStatsDatas", body)
+}
+
+// 統計データ一括取得 (CSV 形式) のレスポンスを RESULT を確認せずに返す。
+func (c *ApiClient) getSimpleStatsDatasStream(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (io.ReadCloser, error) {
+	if err := c.CommonParams.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateStatsDatas(params, statsDatasSpec); err != nil {
+		return nil, err
+	}
+	query := &ParamsGetStatsDatasRoot{
+		CommonParams:        c.CommonParams,
+		ParamsGetStatsDatas: params,
+	}
+
+	if sc, ok := c.HttpClient.(IHttpStreamClient); ok {
+		statusCode, body, err := sc.PostJsonWithQueryStream(ctx, "/getSimpleStatsDatas", query, statsDatasSpec)
+		if err != nil {
+			return nil, err
+		}
+		if !isSuccessHTTPStatus(statusCode) {
+			body.Close()
+			return nil, decodeError("/getSimpleStatsDatas", statusCode, nil)
+		}
+		return body, nil
+	}
+
+	statusCode, body, err := c.HttpClient.PostJsonWithQuery(ctx, "/getSimpleStatsDatas", query, statsDatasSpec)
+	if err != nil {
+		return nil, err
+	}
+	if !isSuccessHTTPStatus(statusCode) {
+		return nil, decodeError("/getSimpleStatsDatas", statusCode, nil)
+	}
+
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+// ストリームの先頭の RESULT を確認し、先頭から読み込める io.ReadCloser を返す。
+//
+// STATUS がエラーの場合は body を閉じる。
+func (c *ApiClient) checkStreamResult(endpoint string, body io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReaderSize(body, resultHeadSize)
+	head, _ := br.Peek(resultHeadSize)
+	stream := struct {
+		io.Reader
+		io.Closer
+	}{br, body}
+
+	result, ok := simpleStatsDataResult(head)
+	if !ok {
+		return stream, nil
+	}
+	if err := c.checkResult(endpoint, http.StatusOK, result); err != nil {
+		if result.Status >= resultStatusError {
+			body.Close()
+			return nil, err
+		}
+		return stream, err
+	}
+	return stream, nil
+}
+
+// CSV 形式のレスポンスの先頭部分から RESULT を読み込む。
+func simpleStatsDataResult(head []byte) (ResponseResult, bool) {
+	sr := NewSimpleStatsDataReader(bytes.NewReader(head))
+	// RESULT は最初のセクションなので、データの行か末尾まで読めばよい
+	sr.Read()
+	data := SimpleStatsData{Header: sr.Header()}
+	return data.Result()
+}
@@ -0,0 +1,198 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func TestGetSimpleStatsData(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  core.ParamsGetSimpleStatsData
+		want core.SimpleStatsData
+	}{
+		{
+			name: "Parse",
+			arg: core.ParamsGetSimpleStatsData{
+				ParamsGetStatsData: core.ParamsGetStatsData{
+					StatsDataId: "0003109741",
+					Limit:       3,
+				},
+				SectionHeaderFlg: "1",
+			},
+			want: core.SimpleStatsData{
+				Header: []core.SimpleStatsDataSection{
+					{
+						Name: "RESULT",
+						Records: [][]string{
+							{"STATUS", "0"},
+							{"ERROR_MSG", "正常に終了しました。"},
+							{"DATE", "2022-11-03T01:49:44.368+09:00"},
+						},
+					},
+					{
+						Name: "PARAMETER",
+						Records: [][]string{
+							{"LANG", "J"},
+							{"STATS_DATA_ID", "0003109741"},
+							{"DATA_FORMAT", "C"},
+							{"START_POSITION", "1"},
+							{"LIMIT", "3"},
+							{"METAGET_FLG", "Y"},
+							{"SECTION_HEADER_FLG", "1"},
+						},
+					},
+					{
+						Name: "STATISTICAL_DATA",
+						Records: [][]string{
+							{"TOTAL_NUMBER", "2508"},
+							{"FROM_NUMBER", "1"},
+							{"TO_NUMBER", "3"},
+							{"NEXT_KEY", "4"},
+							{"TABLE_INF", "0003109741"},
+							{"STAT_NAME", "00100409", "国民経済計算"},
+							{"GOV_ORG", "00100", "内閣府"},
+							{"STATISTICS_NAME", "四半期別ＧＤＰ速報"},
+						},
+					},
+				},
+				Columns: []string{"tab_code", "表章項目", "cat01_code", "国内総生産_名目原系列", "time_code", "時間軸（四半期）", "unit", "value", "annotation"},
+				Rows: [][]string{
+					{"11", "金額", "11", "国内総生産(支出側)", "1994000103", "1994年1～3月期", "10億円", "123456.1", ""},
+					{"11", "金額", "11", "国内総生産(支出側)", "1994000406", "1994年4～6月期", "10億円", "124896.6", ""},
+					{"11", "金額", "11", "国内総生産(支出側)", "1994000709", "1994年7～9月期", "10億円", "125738.4", ""},
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	hc := mockHttpClient{}
+	ac := core.NewApiClient(&hc, core.CommonParams{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := ac.GetSimpleStatsData(ctx, tt.arg)
+			if !reflect.DeepEqual(got, &tt.want) {
+				t.Errorf("GetSimpleStatsData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimpleStatsDataReader(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		want []map[string]string
+	}{
+		{
+			name: "WithoutSectionHeader",
+			arg: "\ufeff" + `"tab_code","表章項目","time_code","時間軸（四半期）","unit","value","annotation"
+"11","金額","1994000103","1994年1～3月期","10億円","123456.1",""
+"11","金額","1994000406","1994年4～6月期","10億円","-","†"
+`,
+			want: []map[string]string{
+				{"tab_code": "11", "表章項目": "金額", "time_code": "1994000103", "時間軸（四半期）": "1994年1～3月期", "unit": "10億円", "value": "123456.1", "annotation": ""},
+				{"tab_code": "11", "表章項目": "金額", "time_code": "1994000406", "時間軸（四半期）": "1994年4～6月期", "unit": "10億円", "value": "-", "annotation": "†"},
+			},
+		},
+		{
+			name: "SingleColumn",
+			arg: `"RESULT"
+"STATUS","0"
+
+"VALUE"
+"time_code"
+"AREA"
+"1994000103"
+`,
+			want: []map[string]string{
+				{"time_code": "AREA"},
+				{"time_code": "1994000103"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := core.NewSimpleStatsDataReader(strings.NewReader(tt.arg))
+
+			var got []map[string]string
+			for {
+				record, err := r.ReadMap()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("ReadMap() error = %v", err)
+				}
+				got = append(got, record)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSimpleStatsDataReaderResult(t *testing.T) {
+	noData := "\"RESULT\"\n\"STATUS\",\"1\"\n\"ERROR_MSG\",\"正常に終了しましたが、該当データはありませんでした。\"\n"
+	invalidAppID := "\"RESULT\"\n\"STATUS\",\"100\"\n\"ERROR_MSG\",\"認証に失敗しました。アプリケーションIDを確認して下さい。\"\n"
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+		wantNil bool
+	}{
+		{name: "OK", body: string(responseGetSimpleStatsData)},
+		{name: "No data", body: noData, wantErr: core.ErrNoData},
+		{name: "Invalid appId", body: invalidAppID, wantErr: core.ErrInvalidAppID, wantNil: true},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := newCountingHttpClient()
+			hc.resp = func(path string) (int, []byte) {
+				return http.StatusOK, []byte(tt.body)
+			}
+			ac := core.NewApiClient(hc, core.CommonParams{})
+
+			readers := map[string]func() (io.ReadCloser, error){
+				"GetSimpleStatsDataReader": func() (io.ReadCloser, error) {
+					return ac.GetSimpleStatsDataReader(ctx, core.ParamsGetSimpleStatsData{})
+				},
+				"GetSimpleStatsDatasReader": func() (io.ReadCloser, error) {
+					return ac.GetSimpleStatsDatasReader(ctx, core.ParamsGetStatsDatas{}, []core.StatsDatasSpec{{StatsDataId: "0003109741"}})
+				},
+			}
+			for name, read := range readers {
+				body, err := read()
+				if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+					t.Errorf("%s() error = %v, want %v", name, err, tt.wantErr)
+				}
+				if tt.wantNil {
+					if body != nil {
+						t.Errorf("%s() = %v, want nil", name, body)
+					}
+					continue
+				}
+				got, _ := io.ReadAll(body)
+				body.Close()
+				if string(got) != tt.body {
+					t.Errorf("%s() body = %q, want %q", name, got, tt.body)
+				}
+			}
+		})
+	}
+}
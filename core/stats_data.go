@@ -2,20 +2,38 @@ package core
 
 import (
 	"context"
-	"encoding/xml"
+	"errors"
+	"fmt"
 )
 
 type ParamsGetStatsData struct {
 	DataSetID         string `json:"dataSetId,omitempty" xml:"DATA_SET_ID"`
 	StatsDataId       string `url:"statsDataId,omitempty" xml:"STATS_DATA_ID"`
 	NarrowingConditon `xml:"NARROWING_COND"`
-	StartPosition     int    `url:"startPosition,omitempty" xml:"START_POSITION"`
-	Limit             int    `url:"limit,omitempty" xml:"LIMIT"`
-	MetaGetFlg        string `url:"metaGetFlg,omitempty" xml:"METAGET_FLG"`
-	CntGetFlg         string `url:"cntGetFlg,omitempty" xml:"CNT_GET_FLG"`
-	ExplanationGetFlg string `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
-	AnnotationGetFlg  string `url:"annotationGetFlg,omitempty" xml:"ANNOTATION_GET_FLG"`
-	ReplaceSpChar     int    `url:"replaceSpChar,omitempty" xml:"REPLACE_SP_CHAR"`
+	StartPosition     int  `url:"startPosition,omitempty" xml:"START_POSITION"`
+	Limit             int  `url:"limit,omitempty" xml:"LIMIT"`
+	MetaGetFlg        Flag `url:"metaGetFlg,omitempty" xml:"METAGET_FLG"`
+	CntGetFlg         Flag `url:"cntGetFlg,omitempty" xml:"CNT_GET_FLG"`
+	ExplanationGetFlg Flag `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
+	AnnotationGetFlg  Flag `url:"annotationGetFlg,omitempty" xml:"ANNOTATION_GET_FLG"`
+	ReplaceSpChar     int  `url:"replaceSpChar,omitempty" xml:"REPLACE_SP_CHAR"`
+}
+
+// パラメータを検証します。
+func (p ParamsGetStatsData) Validate() error {
+	var v paramValidator
+	p.validate(&v)
+	return v.err()
+}
+
+func (p ParamsGetStatsData) validate(v *paramValidator) {
+	v.nonNegative("startPosition", p.StartPosition)
+	v.nonNegative("limit", p.Limit)
+	v.flag("metaGetFlg", p.MetaGetFlg)
+	v.flag("cntGetFlg", p.CntGetFlg)
+	v.flag("explanationGetFlg", p.ExplanationGetFlg)
+	v.flag("annotationGetFlg", p.AnnotationGetFlg)
+	v.replaceSpChar(p.ReplaceSpChar)
 }
 
 type ParamsGetStatsDataRoot struct {
@@ -50,7 +68,10 @@ type ResponseGetStatsDataRoot struct {
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_3
 func (c *ApiClient) GetStatsData(ctx context.Context, params ParamsGetStatsData) (*ResponseGetStatsDataRoot, error) {
-	_, body, err := c.HttpClient.Get(ctx, "/getStatsData", ParamsGetStatsDataRoot{
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.Get(ctx, c.path("/getStatsData"), ParamsGetStatsDataRoot{
 		CommonParams:       c.CommonParams,
 		ParamsGetStatsData: params,
 	})
@@ -59,11 +80,11 @@ func (c *ApiClient) GetStatsData(ctx context.Context, params ParamsGetStatsData)
 	}
 
 	var data *ResponseGetStatsDataRoot
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/getStatsData", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/getStatsData", statusCode, data.Result)
 }
 
 type StatsDatasSpec struct {
@@ -73,51 +94,122 @@ type StatsDatasSpec struct {
 	Limit             int `url:"limit,omitempty" json:"limit,omitempty" xml:"LIMIT"`
 }
 
+// パラメータを検証します。
+func (s StatsDatasSpec) Validate() error {
+	var v paramValidator
+	v.nonNegative("startPosition", s.StartPosition)
+	v.nonNegative("limit", s.Limit)
+	return v.err()
+}
+
 type ParamsGetStatsDatas struct {
-	MetaGetFlg        string `url:"metaGetFlg,omitempty" json:"metaGetFlg,omitempty" xml:"META_GET_FLG"`
-	ExplanationGetFlg string `url:"explanationGetFlg,omitempty" json:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
-	AnnotationGetFlg  string `url:"annotationGetFlg,omitempty" json:"annotationGetFlg,omitempty" xml:"ANNOTATION_GET_FLG"`
+	MetaGetFlg        Flag   `url:"metaGetFlg,omitempty" json:"metaGetFlg,omitempty" xml:"META_GET_FLG"`
+	ExplanationGetFlg Flag   `url:"explanationGetFlg,omitempty" json:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG"`
+	AnnotationGetFlg  Flag   `url:"annotationGetFlg,omitempty" json:"annotationGetFlg,omitempty" xml:"ANNOTATION_GET_FLG"`
 	ReplaceSpChar     int    `url:"replaceSpChar,omitempty" json:"replaceSpChar,omitempty" xml:"REPLACE_SP_CHAR"`
 	SectionHeaderFlg  string `url:"sectionHeaderFlg,omitempty" json:"sectionHeaderFlg,omitempty" xml:"SECTION_HEADER_FLG"`
 	DataSetID         string `url:"dataSetId,omitempty" json:"dataSetId,omitempty" xml:"DATA_SET_ID"`
 	// StatsDatasSpec    []StatsDatasSpec `json:"statsDatasSpec" xml:"STATS_DATAS_SPEC"`
 }
 
+// パラメータを検証します。
+func (p ParamsGetStatsDatas) Validate() error {
+	var v paramValidator
+	v.flag("metaGetFlg", p.MetaGetFlg)
+	v.flag("explanationGetFlg", p.ExplanationGetFlg)
+	v.flag("annotationGetFlg", p.AnnotationGetFlg)
+	v.replaceSpChar(p.ReplaceSpChar)
+	v.sectionHeaderFlg(p.SectionHeaderFlg)
+	return v.err()
+}
+
+// パラメータとリクエストを検証する。
+func validateStatsDatas(params ParamsGetStatsDatas, specs []StatsDatasSpec) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	var v paramValidator
+	v.check(len(specs) <= MaxStatsDatasSpecs, "statsDatasSpec", len(specs), fmt.Sprintf("must not exceed %d requests", MaxStatsDatasSpecs))
+	for i, spec := range specs {
+		err := spec.Validate()
+		if err == nil {
+			continue
+		}
+		var errs ParamErrors
+		if !errors.As(err, &errs) {
+			return fmt.Errorf("statsDatasSpec[%d]: %w", i, err)
+		}
+		for _, e := range errs {
+			e.Param = fmt.Sprintf("statsDatasSpec[%d].%s", i, e.Param)
+			v.errs = append(v.errs, e)
+		}
+	}
+	return v.err()
+}
+
 type ParamsGetStatsDatasRoot struct {
 	CommonParams
 	ParamsGetStatsDatas
 }
 
 type ResponseGetStatsParameter struct {
-	RequestNumber string `xml:"requestNo,attr"`
-	ParamsGetStatsDatas
+	RequestNumber int `xml:"requestNo,attr"`
+	StatsDatasSpec
 }
 
 type ResponseGetStatsParameterList struct {
 	CommonParams
+	ParamsGetStatsDatas
 	Parameter []ResponseGetStatsParameter `xml:"PARAMETER"`
 }
 
+// リクエストごとの結果情報
+type StatsDatasResultInf struct {
+	RequestNumber int    `xml:"requestNo,attr"`
+	Status        int    `xml:"STATUS"`
+	ErrorMsg      string `xml:"ERROR_MSG"`
+	ResultInf
+}
+
+// リクエストごとの統計表情報
+type StatsDatasTableInf struct {
+	RequestNumber int `xml:"requestNo,attr"`
+	TableInf
+}
+
+// リクエストごとのメタ情報
+type StatsDatasClassInf struct {
+	RequestNumber int `xml:"requestNo,attr"`
+	ClassInf
+}
+
+// リクエストごとの統計データ
+type StatsDatasDataInf struct {
+	RequestNumber int `xml:"requestNo,attr"`
+	DataInf
+}
+
+type ResponseGetStatsDataStatisticalDataListResultList struct {
+	ResultInf []StatsDatasResultInf `xml:"RESULT_INF"`
+}
+
 type ResponseGetStatsDataStatisticalDataListTableList struct {
-	RequestNumber string     `xml:"requestNo,attr"`
-	TableInf      []TableInf `xml:"TABLE_INF"`
+	TableInf []StatsDatasTableInf `xml:"TABLE_INF"`
 }
 
 type ResponseGetStatsDataStatisticalDataListClassList struct {
-	RequestNumber string     `xml:"requestNo,attr"`
-	ClassInf      []ClassInf `xml:"CLASS_INF"`
+	ClassInf []StatsDatasClassInf `xml:"CLASS_INF"`
 }
 
 type ResponseGetStatsDataStatisticalDataListDataList struct {
-	RequestNumber string    `xml:"requestNo,attr"`
-	DataInf       []DataInf `xml:"DATA_INF"`
+	DataInf []StatsDatasDataInf `xml:"DATA_INF"`
 }
 
 type ResponseGetStatsDataStatisticalDataList struct {
-	ResultInf    ResultInf                                        `xml:"RESULT_INF"`
-	TableInfList ResponseGetStatsDataStatisticalDataListTableList `xml:"TABLE_INF_LIST"`
-	ClassInfList ResponseGetStatsDataStatisticalDataListClassList `xml:"CLASS_INF_LIST"`
-	DataInfList  ResponseGetStatsDataStatisticalDataListDataList  `xml:"DATA_INF_LIST"`
+	ResultInfList ResponseGetStatsDataStatisticalDataListResultList `xml:"RESULT_INF_LIST"`
+	TableInfList  ResponseGetStatsDataStatisticalDataListTableList  `xml:"TABLE_INF_LIST"`
+	ClassInfList  ResponseGetStatsDataStatisticalDataListClassList  `xml:"CLASS_INF_LIST"`
+	DataInfList   ResponseGetStatsDataStatisticalDataListDataList   `xml:"DATA_INF_LIST"`
 }
 
 type ResponseGetStatsDatas struct {
@@ -127,14 +219,20 @@ type ResponseGetStatsDatas struct {
 }
 
 type ResponseGetStatsDatasRoot struct {
-	ResponseGetStatsData `xml:"GET_STATS_DATAS"`
+	ResponseGetStatsDatas `xml:"GET_STATS_DATAS"`
 }
 
 // 統計データ一括取得
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_7
-func (c *ApiClient) GetStatsDatas(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (*ResponseGetStatsData, error) {
-	_, body, err := c.HttpClient.PostJsonWithQuery(ctx, "/getStatsDatas", &ParamsGetStatsDatasRoot{
+func (c *ApiClient) GetStatsDatas(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) (*ResponseGetStatsDatasRoot, error) {
+	if err := c.CommonParams.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateStatsDatas(params, statsDatasSpec); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.PostJsonWithQuery(ctx, c.path("/getStatsDatas"), &ParamsGetStatsDatasRoot{
 		CommonParams:        c.CommonParams,
 		ParamsGetStatsDatas: params,
 	}, statsDatasSpec)
@@ -142,10 +240,10 @@ func (c *ApiClient) GetStatsDatas(ctx context.Context, params ParamsGetStatsData
 		return nil, err
 	}
 
-	var data *ResponseGetStatsData
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	var data *ResponseGetStatsDatasRoot
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/getStatsDatas", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/getStatsDatas", statusCode, data.Result)
 }
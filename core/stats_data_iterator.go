@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ページの取得に失敗した場合のエラー
+type PageError struct {
+	StartPosition int
+	Err           error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("e-stat: page starting at %d: %v", e.StartPosition, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// 統計データを NEXT_KEY に従って 1 件ずつ取得します。
+//
+// 該当データがない (STATUS が 1) 場合はエラーにせず終了します。
+//
+//	it := ac.IterateStatsData(ctx, params)
+//	for it.Next() {
+//		v := it.Value()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type StatsDataIterator struct {
+	ctx    context.Context
+	client IApiClient
+	params ParamsGetStatsData
+
+	first  *ResponseGetStatsDataRoot
+	values []DataInfValue
+	index  int
+	value  DataInfValue
+	done   bool
+	err    error
+}
+
+// 統計データ取得をページ送りしながら繰り返します。
+//
+// 2 ページ目以降はメタ情報が不要なため MetaGetFlg を FlagNo にして取得します。
+func (c *ApiClient) IterateStatsData(ctx context.Context, params ParamsGetStatsData) *StatsDataIterator {
+	return &StatsDataIterator{
+		ctx:    ctx,
+		client: c,
+		params: params,
+	}
+}
+
+// 次の値に進みます。値がなくなるか、エラーが発生すると false を返します。
+func (it *StatsDataIterator) Next() bool {
+	for it.index >= len(it.values) {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.value = it.values[it.index]
+	it.index++
+	return true
+}
+
+func (it *StatsDataIterator) fetch() {
+	startPosition := it.params.StartPosition
+	if startPosition == 0 {
+		startPosition = 1
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = &PageError{StartPosition: startPosition, Err: err}
+		return
+	}
+
+	page, err := it.client.GetStatsData(it.ctx, it.params)
+	if errors.Is(err, ErrNoData) {
+		if it.first == nil {
+			it.first = page
+		}
+		it.done = true
+		return
+	}
+	if err != nil {
+		it.err = &PageError{StartPosition: startPosition, Err: err}
+		return
+	}
+
+	if it.first == nil {
+		it.first = page
+		it.params.MetaGetFlg = FlagNo
+	}
+
+	it.values = page.DataList.Data.Value
+	it.index = 0
+
+	if page.DataList.Result.NextKey == 0 {
+		it.done = true
+	} else {
+		it.params.StartPosition = page.DataList.Result.NextKey
+	}
+}
+
+// 現在の値を返します。
+func (it *StatsDataIterator) Value() DataInfValue {
+	return it.value
+}
+
+// 繰り返し中に発生したエラーを返します。
+func (it *StatsDataIterator) Err() error {
+	return it.err
+}
+
+// 最初のページの統計表情報を返します。最初の Next の後に利用できます。
+func (it *StatsDataIterator) Table() TableInf {
+	if it.first == nil {
+		return TableInf{}
+	}
+	return it.first.DataList.Table
+}
+
+// 最初のページのメタ情報を返します。最初の Next の後に利用できます。
+func (it *StatsDataIterator) Class() ClassInf {
+	if it.first == nil {
+		return ClassInf{}
+	}
+	return it.first.DataList.Class
+}
+
+// 最初のページの特殊文字の注記を返します。最初の Next の後に利用できます。
+func (it *StatsDataIterator) Note() []DataInfNote {
+	if it.first == nil {
+		return nil
+	}
+	return it.first.DataList.Data.Note
+}
@@ -0,0 +1,154 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+)
+
+// 1 ページ 2 件で値を返す IHttpClient。do を estattest.ClientFunc として使う
+type pagedHttpClient struct {
+	values   []string
+	failAt   int
+	requests []core.ParamsGetStatsData
+}
+
+func (hc *pagedHttpClient) do(ctx context.Context, r estattest.ClientRequest) (int, []byte, error) {
+	if r.Path != "/getStatsData" {
+		return http.StatusNotFound, nil, nil
+	}
+	params := r.Query.(core.ParamsGetStatsDataRoot).ParamsGetStatsData
+	hc.requests = append(hc.requests, params)
+
+	start := params.StartPosition
+	if start == 0 {
+		start = 1
+	}
+	if start == hc.failAt {
+		return http.StatusInternalServerError, nil, nil
+	}
+	if len(hc.values) == 0 {
+		return http.StatusOK, []byte(`<GET_STATS_DATA><RESULT><STATUS>1</STATUS></RESULT></GET_STATS_DATA>`), nil
+	}
+
+	end := start + 1
+	if end > len(hc.values) {
+		end = len(hc.values)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<GET_STATS_DATA><RESULT><STATUS>0</STATUS></RESULT><STATISTICAL_DATA><RESULT_INF>`)
+	fmt.Fprintf(&b, `<FROM_NUMBER>%d</FROM_NUMBER><TO_NUMBER>%d</TO_NUMBER>`, start, end)
+	if end < len(hc.values) {
+		fmt.Fprintf(&b, `<NEXT_KEY>%d</NEXT_KEY>`, end+1)
+	}
+	b.WriteString(`</RESULT_INF>`)
+	if params.MetaGetFlg != "N" {
+		b.WriteString(`<TABLE_INF id="0003109741"/><CLASS_INF><CLASS_OBJ id="time"/></CLASS_INF>`)
+	}
+	b.WriteString(`<DATA_INF>`)
+	for _, v := range hc.values[start-1 : end] {
+		fmt.Fprintf(&b, `<VALUE time="%s">%s</VALUE>`, v, v)
+	}
+	b.WriteString(`</DATA_INF></STATISTICAL_DATA></GET_STATS_DATA>`)
+
+	return http.StatusOK, []byte(b.String()), nil
+}
+
+func TestIterateStatsData(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       []string
+		failAt       int
+		want         []string
+		wantRequests []core.ParamsGetStatsData
+		wantErr      bool
+	}{
+		{
+			name:   "AllPages",
+			values: []string{"1", "2", "3", "4", "5"},
+			want:   []string{"1", "2", "3", "4", "5"},
+			wantRequests: []core.ParamsGetStatsData{
+				{StatsDataId: "0003109741"},
+				{StatsDataId: "0003109741", StartPosition: 3, MetaGetFlg: "N"},
+				{StatsDataId: "0003109741", StartPosition: 5, MetaGetFlg: "N"},
+			},
+		},
+		{
+			name:   "PageError",
+			values: []string{"1", "2", "3", "4", "5"},
+			failAt: 3,
+			want:   []string{"1", "2"},
+			wantRequests: []core.ParamsGetStatsData{
+				{StatsDataId: "0003109741"},
+				{StatsDataId: "0003109741", StartPosition: 3, MetaGetFlg: "N"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "NoData",
+			wantRequests: []core.ParamsGetStatsData{
+				{StatsDataId: "0003109741"},
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := pagedHttpClient{values: tt.values, failAt: tt.failAt}
+			ac := core.NewApiClient(estattest.ClientFunc(hc.do), core.CommonParams{})
+
+			it := ac.IterateStatsData(ctx, core.ParamsGetStatsData{StatsDataId: "0003109741"})
+			var got []string
+			for it.Next() {
+				got = append(got, it.Value().Value)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IterateStatsData() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(hc.requests, tt.wantRequests) {
+				t.Errorf("IterateStatsData() requests = %+v, want %+v", hc.requests, tt.wantRequests)
+			}
+			if len(tt.want) > 0 && (it.Table().ID != "0003109741" || len(it.Class().ClassObj) != 1) {
+				t.Errorf("IterateStatsData() metadata = %+v, %+v", it.Table(), it.Class())
+			}
+
+			var pageErr *core.PageError
+			if got := errors.As(it.Err(), &pageErr); got != tt.wantErr {
+				t.Fatalf("IterateStatsData() error = %v, wantErr %v", it.Err(), tt.wantErr)
+			}
+			if tt.wantErr && pageErr.StartPosition != tt.failAt {
+				t.Errorf("IterateStatsData() error start = %d, want %d", pageErr.StartPosition, tt.failAt)
+			}
+		})
+	}
+}
+
+func TestIterateStatsDataCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	hc := pagedHttpClient{values: []string{"1"}}
+	ac := core.NewApiClient(estattest.ClientFunc(hc.do), core.CommonParams{})
+
+	it := ac.IterateStatsData(ctx, core.ParamsGetStatsData{})
+	if it.Next() {
+		t.Errorf("IterateStatsData() Next() = true, want false")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("IterateStatsData() error = %v, want %v", it.Err(), context.Canceled)
+	}
+	if len(hc.requests) != 0 {
+		t.Errorf("IterateStatsData() requests = %d, want 0", len(hc.requests))
+	}
+}
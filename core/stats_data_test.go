@@ -40,8 +40,9 @@ func TestGetStatsData(t *testing.T) {
 					},
 					DataList: core.ResponseGetStatsDataStatisticalData{
 						Result: core.ResultInf{
-							FromNumber: 1,
-							ToNumber:   2508,
+							TotalNumber: 2508,
+							FromNumber:  1,
+							ToNumber:    2508,
 						},
 						Table: core.TableInf{
 							ID: "0003109741",
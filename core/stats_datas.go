@@ -0,0 +1,231 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 統計データ一括取得の 1 回のリクエストで指定できる StatsDatasSpec の上限
+const MaxStatsDatasSpecs = 100
+
+// GetStatsDatasAll の同時実行数の既定値
+const DefaultStatsDatasConcurrency = 4
+
+// GetStatsDatasAll の同時実行数を指定します。
+func WithStatsDatasConcurrency(concurrency int) ApiOption {
+	return func(c *ApiClient) {
+		c.StatsDatasConcurrency = concurrency
+	}
+}
+
+// 統計データ一括取得の 1 リクエスト分の結果
+type StatsDatasResult struct {
+	// リクエスト番号 (1 から始まる StatsDatasSpec の順番)
+	RequestNumber int
+	Spec          StatsDatasSpec
+
+	// 統計データ取得と同じ形式にまとめた結果
+	StatisticalData ResponseGetStatsDataStatisticalData
+
+	// STATUS が 0 以外の場合は *APIError、結果が含まれていない場合はそのエラー
+	Err error
+}
+
+// リクエストの結果が含まれていない場合のエラー
+type MissingRequestError struct {
+	RequestNumber int
+}
+
+func (e *MissingRequestError) Error() string {
+	return fmt.Sprintf("e-stat: /getStatsDatas: no result for request %d", e.RequestNumber)
+}
+
+// TABLE_INF_LIST、CLASS_INF_LIST、DATA_INF_LIST を requestNo ごとにまとめ、
+// specs と同じ順番で返します。
+//
+// 一括取得全体がエラーでリクエストごとの結果がない場合は、RESULT の *APIError を Err に設定します。
+func (d *ResponseGetStatsDatas) Results(specs []StatsDatasSpec) []StatsDatasResult {
+	return d.results(specs, 0)
+}
+
+// Results と同じだが、RequestNumber を offset だけずらして設定する。
+//
+// GetStatsDatasAll で分割したリクエストの番号を、分割前の番号に合わせるために使う。
+func (d *ResponseGetStatsDatas) results(specs []StatsDatasSpec, offset int) []StatsDatasResult {
+	results := make([]StatsDatasResult, len(specs))
+	found := make([]bool, len(specs))
+	for i, spec := range specs {
+		results[i] = StatsDatasResult{RequestNumber: offset + i + 1, Spec: spec}
+	}
+
+	// requestNo は 1 から始まる
+	index := func(requestNumber int) (int, bool) {
+		i := requestNumber - 1
+		return i, i >= 0 && i < len(results)
+	}
+
+	list := d.StatisticalDataList
+	for _, r := range list.ResultInfList.ResultInf {
+		if i, ok := index(r.RequestNumber); ok {
+			found[i] = true
+			results[i].StatisticalData.Number = r.TotalNumber
+			results[i].StatisticalData.Result = r.ResultInf
+			if r.Status != ResultStatusOK {
+				results[i].Err = &APIError{
+					Endpoint: "/getStatsDatas",
+					Status:   r.Status,
+					ErrorMsg: r.ErrorMsg,
+					Date:     d.Result.Date,
+				}
+			}
+		}
+	}
+	for _, t := range list.TableInfList.TableInf {
+		if i, ok := index(t.RequestNumber); ok {
+			found[i] = true
+			results[i].StatisticalData.Table = t.TableInf
+		}
+	}
+	for _, c := range list.ClassInfList.ClassInf {
+		if i, ok := index(c.RequestNumber); ok {
+			found[i] = true
+			results[i].StatisticalData.Class = c.ClassInf
+		}
+	}
+	for _, v := range list.DataInfList.DataInf {
+		if i, ok := index(v.RequestNumber); ok {
+			found[i] = true
+			results[i].StatisticalData.Data = v.DataInf
+		}
+	}
+
+	for i := range results {
+		if found[i] {
+			continue
+		}
+		if d.Result.Status != ResultStatusOK && d.Result.Status != ResultStatusPartialError {
+			results[i].Err = &APIError{
+				Endpoint: "/getStatsDatas",
+				Status:   d.Result.Status,
+				ErrorMsg: d.Result.ErrorMsg,
+				Date:     d.Result.Date,
+			}
+		} else {
+			results[i].Err = &MissingRequestError{RequestNumber: offset + i + 1}
+		}
+	}
+
+	return results
+}
+
+// GetStatsDatasAll で一部のリクエストが失敗した場合のエラー
+type StatsDatasError struct {
+	// StatsDatasSpec のインデックスをキーとしたエラー
+	Errs map[int]error
+}
+
+func (e *StatsDatasError) Error() string {
+	indexes := make([]int, 0, len(e.Errs))
+	for i := range e.Errs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	msgs := make([]string, len(indexes))
+	for n, i := range indexes {
+		msgs[n] = fmt.Sprintf("[%d] %v", i, e.Errs[i])
+	}
+	return fmt.Sprintf("e-stat: /getStatsDatas: %d requests failed: %s", len(e.Errs), strings.Join(msgs, "; "))
+}
+
+func (e *StatsDatasError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// 統計データ一括取得を MaxStatsDatasSpecs 件ずつに分けて並行に実行し、
+// 結果を statsDatasSpec と同じ順番で返します。
+//
+// 失敗したリクエストがある場合は、すべての結果とともに *StatsDatasError を返します。
+// 警告 (STATUS 1〜99) は WarningPolicy に従います。
+func (c *ApiClient) GetStatsDatasAll(ctx context.Context, params ParamsGetStatsDatas, statsDatasSpec []StatsDatasSpec) ([]StatsDatasResult, error) {
+	concurrency := c.StatsDatasConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultStatsDatasConcurrency
+	}
+
+	results := make([]StatsDatasResult, len(statsDatasSpec))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(statsDatasSpec); start += MaxStatsDatasSpecs {
+		end := start + MaxStatsDatasSpecs
+		if end > len(statsDatasSpec) {
+			end = len(statsDatasSpec)
+		}
+
+		wg.Add(1)
+		go func(start int, specs []StatsDatasSpec) {
+			defer wg.Done()
+
+			var batch []StatsDatasResult
+			select {
+			case sem <- struct{}{}:
+				batch = c.getStatsDatasBatch(ctx, params, specs, start)
+				<-sem
+			case <-ctx.Done():
+				batch = failedStatsDatasResults(specs, start, ctx.Err())
+			}
+			copy(results[start:], batch)
+		}(start, statsDatasSpec[start:end])
+	}
+	wg.Wait()
+
+	errs := map[int]error{}
+	for i, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		var apiErr *APIError
+		if errors.As(r.Err, &apiErr) && apiErr.IsWarning() && c.WarningPolicy == WarningIgnore {
+			continue
+		}
+		errs[i] = r.Err
+	}
+	if len(errs) > 0 {
+		return results, &StatsDatasError{Errs: errs}
+	}
+	return results, nil
+}
+
+func (c *ApiClient) getStatsDatasBatch(ctx context.Context, params ParamsGetStatsDatas, specs []StatsDatasSpec, offset int) []StatsDatasResult {
+	if err := ctx.Err(); err != nil {
+		return failedStatsDatasResults(specs, offset, err)
+	}
+
+	data, err := c.GetStatsDatas(ctx, params, specs)
+	if err != nil {
+		// 警告の場合はレスポンスにリクエストごとの結果が含まれる
+		var apiErr *APIError
+		if data == nil || !errors.As(err, &apiErr) || !apiErr.IsWarning() {
+			return failedStatsDatasResults(specs, offset, err)
+		}
+	}
+
+	return data.results(specs, offset)
+}
+
+func failedStatsDatasResults(specs []StatsDatasSpec, offset int, err error) []StatsDatasResult {
+	results := make([]StatsDatasResult, len(specs))
+	for i, spec := range specs {
+		results[i] = StatsDatasResult{RequestNumber: offset + i + 1, Spec: spec, Err: err}
+	}
+	return results
+}
@@ -0,0 +1,225 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+)
+
+func TestGetStatsDatas(t *testing.T) {
+	ctx := context.Background()
+	hc := mockHttpClient{}
+	ac := core.NewApiClient(&hc, core.CommonParams{})
+
+	specs := []core.StatsDatasSpec{
+		{StatsDataId: "0003109741", StartPosition: 1, Limit: 2},
+		{StatsDataId: "0000000000"},
+		{StatsDataId: "0003090287"},
+	}
+
+	data, err := ac.GetStatsDatas(ctx, core.ParamsGetStatsDatas{}, specs)
+	if !errors.Is(err, core.ErrPartialError) {
+		t.Fatalf("GetStatsDatas() error = %v, want %v", err, core.ErrPartialError)
+	}
+
+	// 日時はタイムゾーンの表現によらず同じ時刻であればよい
+	wantDate := time.Date(2022, time.November, 5, 10, 12, 31, 514000000, time.FixedZone("JST", 9*60*60))
+	if !data.Result.Date.Equal(wantDate) {
+		t.Errorf("Result.Date = %v, want %v", data.Result.Date, wantDate)
+	}
+	gotResult := data.Result
+	gotResult.Date = time.Time{}
+	wantResult := core.ResponseResult{
+		Status:   2,
+		ErrorMsg: "正常に終了しましたが、一部にエラーがあります。",
+	}
+	if !reflect.DeepEqual(gotResult, wantResult) {
+		t.Errorf("Result = %v, want %v", data.Result, wantResult)
+	}
+
+	wantParameter := []core.ResponseGetStatsParameter{
+		{RequestNumber: 1, StatsDatasSpec: core.StatsDatasSpec{StatsDataId: "0003109741", StartPosition: 1, Limit: 2}},
+		{RequestNumber: 2, StatsDatasSpec: core.StatsDatasSpec{StatsDataId: "0000000000"}},
+	}
+	if !reflect.DeepEqual(data.ParameterList.Parameter, wantParameter) {
+		t.Errorf("ParameterList.Parameter = %v, want %v", data.ParameterList.Parameter, wantParameter)
+	}
+
+	results := data.Results(specs)
+	if len(results) != len(specs) {
+		t.Fatalf("len(Results()) = %d, want %d", len(results), len(specs))
+	}
+
+	first := results[0]
+	if first.Err != nil {
+		t.Errorf("Results()[0].Err = %v", first.Err)
+	}
+	if first.RequestNumber != 1 || !reflect.DeepEqual(first.Spec, specs[0]) {
+		t.Errorf("Results()[0] = %d %v, want 1 %v", first.RequestNumber, first.Spec, specs[0])
+	}
+	wantResultInf := core.ResultInf{TotalNumber: 2508, FromNumber: 1, ToNumber: 2, NextKey: 3}
+	if !reflect.DeepEqual(first.StatisticalData.Result, wantResultInf) {
+		t.Errorf("Results()[0].StatisticalData.Result = %v, want %v", first.StatisticalData.Result, wantResultInf)
+	}
+	if first.StatisticalData.Table.ID != "0003109741" {
+		t.Errorf("Results()[0].StatisticalData.Table.ID = %s", first.StatisticalData.Table.ID)
+	}
+	if len(first.StatisticalData.Class.ClassObj) != 3 {
+		t.Errorf("len(Results()[0].StatisticalData.Class.ClassObj) = %d, want 3", len(first.StatisticalData.Class.ClassObj))
+	}
+	wantValue := []core.DataInfValue{
+		{Tab: "11", Cat01: "11", Time: "1994000103", Unit: "10億円", Value: "123456.1"},
+		{Tab: "11", Cat01: "11", Time: "1994000406", Unit: "10億円", Value: "124896.6"},
+	}
+	if !reflect.DeepEqual(first.StatisticalData.Data.Value, wantValue) {
+		t.Errorf("Results()[0].StatisticalData.Data.Value = %v, want %v", first.StatisticalData.Data.Value, wantValue)
+	}
+
+	var apiErr *core.APIError
+	if !errors.As(results[1].Err, &apiErr) || apiErr.Status != 100 || apiErr.ErrorMsg != "統計表IDが存在しません。" {
+		t.Errorf("Results()[1].Err = %v", results[1].Err)
+	}
+
+	var missingErr *core.MissingRequestError
+	if !errors.As(results[2].Err, &missingErr) || missingErr.RequestNumber != 3 {
+		t.Errorf("Results()[2].Err = %v", results[2].Err)
+	}
+}
+
+func TestStatsDatasResultsError(t *testing.T) {
+	data := core.ResponseGetStatsDatas{
+		Result: core.ResponseResult{Status: core.ResultStatusInvalidAppID, ErrorMsg: "認証に失敗しました。"},
+	}
+
+	for i, r := range data.Results(make([]core.StatsDatasSpec, 2)) {
+		if !errors.Is(r.Err, core.ErrInvalidAppID) {
+			t.Errorf("Results()[%d].Err = %v, want %v", i, r.Err, core.ErrInvalidAppID)
+		}
+	}
+}
+
+// 一括取得のリクエストごとに STATS_DATA_ID を TABLE_INF の id として返す IHttpClient。do を estattest.ClientFunc として使う
+type bulkHttpClient struct {
+	mu          sync.Mutex
+	calls       int
+	inFlight    int
+	maxInFlight int
+}
+
+func (hc *bulkHttpClient) do(ctx context.Context, r estattest.ClientRequest) (int, []byte, error) {
+	if r.Path != "/getStatsDatas" {
+		return http.StatusNotFound, nil, nil
+	}
+
+	hc.mu.Lock()
+	hc.calls++
+	hc.inFlight++
+	if hc.inFlight > hc.maxInFlight {
+		hc.maxInFlight = hc.inFlight
+	}
+	hc.mu.Unlock()
+
+	defer func() {
+		hc.mu.Lock()
+		hc.inFlight--
+		hc.mu.Unlock()
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	specs := r.Body.([]core.StatsDatasSpec)
+	if len(specs) > core.MaxStatsDatasSpecs {
+		return http.StatusOK, []byte(`<GET_STATS_DATAS><RESULT><STATUS>100</STATUS><ERROR_MSG>too many specs</ERROR_MSG></RESULT></GET_STATS_DATAS>`), nil
+	}
+
+	var results, tables strings.Builder
+	for i, spec := range specs {
+		if spec.StatsDataId == "server-error" {
+			return http.StatusServiceUnavailable, nil, nil
+		}
+
+		if spec.StatsDataId == "missing" {
+			continue
+		}
+
+		status := 0
+		if spec.StatsDataId == "not-found" {
+			status = 101
+		}
+		fmt.Fprintf(&results, `<RESULT_INF requestNo="%d"><STATUS>%d</STATUS></RESULT_INF>`, i+1, status)
+		fmt.Fprintf(&tables, `<TABLE_INF requestNo="%d" id="%s"/>`, i+1, spec.StatsDataId)
+	}
+
+	return http.StatusOK, []byte(fmt.Sprintf(
+		`<GET_STATS_DATAS><RESULT><STATUS>0</STATUS></RESULT><STATISTICAL_DATA_LIST><RESULT_INF_LIST>%s</RESULT_INF_LIST><TABLE_INF_LIST>%s</TABLE_INF_LIST></STATISTICAL_DATA_LIST></GET_STATS_DATAS>`,
+		results.String(), tables.String(),
+	)), nil
+}
+
+func TestGetStatsDatasAll(t *testing.T) {
+	ctx := context.Background()
+
+	specs := make([]core.StatsDatasSpec, 250)
+	for i := range specs {
+		specs[i].StatsDataId = fmt.Sprintf("%010d", i)
+	}
+	specs[120].StatsDataId = "not-found"
+	specs[150].StatsDataId = "missing"
+	specs[230].StatsDataId = "server-error"
+
+	hc := bulkHttpClient{}
+	ac := core.NewApiClient(estattest.ClientFunc(hc.do), core.CommonParams{}, core.WithStatsDatasConcurrency(2))
+
+	results, err := ac.GetStatsDatasAll(ctx, core.ParamsGetStatsDatas{}, specs)
+
+	if hc.calls != 3 {
+		t.Errorf("calls = %d, want 3", hc.calls)
+	}
+	if hc.maxInFlight > 2 {
+		t.Errorf("maxInFlight = %d, want <= 2", hc.maxInFlight)
+	}
+
+	if len(results) != len(specs) {
+		t.Fatalf("len(GetStatsDatasAll()) = %d, want %d", len(results), len(specs))
+	}
+	for i, r := range results[:200] {
+		if r.RequestNumber != i+1 || r.Spec != specs[i] {
+			t.Errorf("GetStatsDatasAll()[%d] = %d %v, want %d %v", i, r.RequestNumber, r.Spec, i+1, specs[i])
+		}
+		if i != 120 && i != 150 && (r.Err != nil || r.StatisticalData.Table.ID != specs[i].StatsDataId) {
+			t.Errorf("GetStatsDatasAll()[%d] = %s %v, want %s", i, r.StatisticalData.Table.ID, r.Err, specs[i].StatsDataId)
+		}
+	}
+
+	var statsDatasErr *core.StatsDatasError
+	if !errors.As(err, &statsDatasErr) {
+		t.Fatalf("GetStatsDatasAll() error = %v, want *StatsDatasError", err)
+	}
+	if len(statsDatasErr.Errs) != 52 {
+		t.Errorf("len(Errs) = %d, want 52", len(statsDatasErr.Errs))
+	}
+	if !errors.Is(statsDatasErr.Errs[120], core.ErrInvalidParameter) {
+		t.Errorf("Errs[120] = %v", statsDatasErr.Errs[120])
+	}
+	// 2 回目のリクエストに含まれていない結果も、分割前の番号で報告する
+	var missingErr *core.MissingRequestError
+	if !errors.As(statsDatasErr.Errs[150], &missingErr) || missingErr.RequestNumber != 151 {
+		t.Errorf("Errs[150] = %v, want request 151", statsDatasErr.Errs[150])
+	}
+	for i := 200; i < 250; i++ {
+		if !errors.Is(statsDatasErr.Errs[i], core.ErrHTTPStatus) || !errors.Is(results[i].Err, core.ErrHTTPStatus) {
+			t.Errorf("Errs[%d] = %v", i, statsDatasErr.Errs[i])
+		}
+	}
+	if !errors.Is(err, core.ErrHTTPStatus) {
+		t.Errorf("GetStatsDatasAll() error = %v, want %v", err, core.ErrHTTPStatus)
+	}
+}
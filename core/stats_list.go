@@ -1,23 +1,33 @@
 package core
 
-import (
-	"context"
-	"encoding/xml"
-)
+import "context"
 
 type ParamsGetStatsList struct {
-	SurveyYears       string `url:"surveyYears,omitempty" xml:"SURVEY_YEARS,omitempty"`
-	OpenYears         string `url:"openYears,omitempty" xml:"OPEN_YEARS,omitempty"`
-	StatsField        int    `url:"statsField,omitempty" xml:"STATS_FIELD,omitempty"`
-	StatsCode         int    `url:"statsCode,omitempty" xml:"STATS_CODE,omitempty"`
-	SearchWord        string `url:"searchWord,omitempty" xml:"SEARCH_WORD,omitempty"`
-	SearchKind        int    `url:"searchKind,omitempty" xml:"SEARCH_KIND,omitempty"`
-	CollectArea       int    `url:"collectArea,omitempty" xml:"COLLECT_AREA,omitempty"`
-	ExplanationGetFlg string `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG,omitempty"`
-	StatsNameList     string `url:"statsNameList,omitempty" xml:"STATS_NAME_LIST,omitempty"`
-	StartPosition     int    `url:"startPosition,omitempty" xml:"START_POSITION,omitempty"`
-	Limit             int    `url:"limit,omitempty" xml:"LIMIT,omitempty"`
-	UpdatedDate       string `url:"updatedDate,omitempty" xml:"UPDATED_DATE,omitempty"`
+	SurveyYears       string      `url:"surveyYears,omitempty" xml:"SURVEY_YEARS,omitempty"`
+	OpenYears         string      `url:"openYears,omitempty" xml:"OPEN_YEARS,omitempty"`
+	StatsField        StatsField  `url:"statsField,omitempty" xml:"STATS_FIELD,omitempty"`
+	StatsCode         int         `url:"statsCode,omitempty" xml:"STATS_CODE,omitempty"`
+	SearchWord        string      `url:"searchWord,omitempty" xml:"SEARCH_WORD,omitempty"`
+	SearchKind        SearchKind  `url:"searchKind,omitempty" xml:"SEARCH_KIND,omitempty"`
+	CollectArea       CollectArea `url:"collectArea,omitempty" xml:"COLLECT_AREA,omitempty"`
+	ExplanationGetFlg Flag        `url:"explanationGetFlg,omitempty" xml:"EXPLANATION_GET_FLG,omitempty"`
+	StatsNameList     string      `url:"statsNameList,omitempty" xml:"STATS_NAME_LIST,omitempty"`
+	StartPosition     int         `url:"startPosition,omitempty" xml:"START_POSITION,omitempty"`
+	Limit             int         `url:"limit,omitempty" xml:"LIMIT,omitempty"`
+	UpdatedDate       string      `url:"updatedDate,omitempty" xml:"UPDATED_DATE,omitempty"`
+}
+
+// パラメータを検証します。
+func (p ParamsGetStatsList) Validate() error {
+	var v paramValidator
+	v.statsField(p.StatsField)
+	v.check(p.SearchKind.Valid(), "searchKind", p.SearchKind, "must be 1 or 2")
+	v.collectArea(p.CollectArea)
+	v.flag("explanationGetFlg", p.ExplanationGetFlg)
+	v.check(p.StatsNameList == "" || p.StatsNameList == "Y", "statsNameList", p.StatsNameList, `must be "Y"`)
+	v.nonNegative("startPosition", p.StartPosition)
+	v.nonNegative("limit", p.Limit)
+	return v.err()
 }
 
 type ParamsGetStatsListRoot struct {
@@ -50,7 +60,10 @@ type ResponseGetStatsListRoot struct {
 //
 // https://www.e-stat.go.jp/api/api-info/e-stat-manual3-0#api_2_1
 func (c *ApiClient) GetStatsList(ctx context.Context, params ParamsGetStatsList) (*ResponseGetStatsListRoot, error) {
-	_, body, err := c.HttpClient.Get(ctx, "/getStatsList", ParamsGetStatsListRoot{
+	if err := c.validate(params); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := c.HttpClient.Get(ctx, c.path("/getStatsList"), ParamsGetStatsListRoot{
 		CommonParams:       c.CommonParams,
 		ParamsGetStatsList: params,
 	})
@@ -59,9 +72,9 @@ func (c *ApiClient) GetStatsList(ctx context.Context, params ParamsGetStatsList)
 	}
 
 	var data *ResponseGetStatsListRoot
-	if err := xml.Unmarshal(body, &data); err != nil {
-		return nil, err
+	if err := c.unmarshal(body, &data); err != nil {
+		return nil, decodeError("/getStatsList", statusCode, err)
 	}
 
-	return data, nil
+	return data, c.checkResult("/getStatsList", statusCode, data.Result)
 }
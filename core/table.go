@@ -0,0 +1,224 @@
+package core
+
+// 分類事項のコードとメタ情報
+type TableDim struct {
+	// 分類事項の ID ("tab"、"cat01"、"area"、"time" など)
+	ID string
+
+	// 分類事項の名前 (ClassObj.Name)
+	ObjName string
+
+	Code       string
+	Name       string
+	Level      string
+	Unit       string
+	ParentCode string
+}
+
+// 統計データの 1 件の値と、各分類事項のメタ情報
+type TableRecord struct {
+	// Table.Dims と同じ順番の分類事項
+	Dims []TableDim
+
+	// 値の単位。VALUE の unit 属性がない場合は分類事項の単位を使います。
+	Unit       string
+	Annotation string
+	Value      string
+}
+
+// id の分類事項を返します。
+func (r TableRecord) Dim(id string) (TableDim, bool) {
+	for _, d := range r.Dims {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return TableDim{}, false
+}
+
+// 統計データを 1 件 1 レコードの縦持ちの表にしたもの
+//
+// 各値の分類事項のコードを CLASS_INF の名前、階層レベル、単位、親コードと結び付けます。
+type Table struct {
+	Info       TableInf
+	Class      ClassInf
+	Note       []DataInfNote
+	Annotation []DataInfAnnotation
+	Records    []TableRecord
+
+	dims    []string
+	objs    map[string]ClassObj
+	classes map[string]map[string]ClassObjClass
+}
+
+// 統計データ取得の結果から Table を作ります。
+func NewTable(data ResponseGetStatsDataStatisticalData) *Table {
+	t := newTable(data.Table, data.Class, data.Data.Note)
+	t.Annotation = data.Data.Annotation
+	t.Append(data.Data.Value...)
+	return t
+}
+
+// StatsDataIterator の値をすべて読み込み、Table を作ります。
+func ReadTable(it *StatsDataIterator) (*Table, error) {
+	var t *Table
+	for it.Next() {
+		if t == nil {
+			t = newTable(it.Table(), it.Class(), it.Note())
+		}
+		t.Append(it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if t == nil {
+		t = newTable(it.Table(), it.Class(), it.Note())
+	}
+	return t, nil
+}
+
+func newTable(info TableInf, class ClassInf, note []DataInfNote) *Table {
+	t := &Table{
+		Info:    info,
+		Class:   class,
+		Note:    note,
+		objs:    map[string]ClassObj{},
+		classes: map[string]map[string]ClassObjClass{},
+	}
+	for _, obj := range class.ClassObj {
+		t.dims = append(t.dims, obj.ID)
+		t.objs[obj.ID] = obj
+
+		classes := make(map[string]ClassObjClass, len(obj.Class))
+		for _, c := range obj.Class {
+			classes[c.Code] = c
+		}
+		t.classes[obj.ID] = classes
+	}
+	return t
+}
+
+// 値を追加します。
+//
+// メタ情報がない場合は、最初に追加した値に含まれる分類事項を使います。
+func (t *Table) Append(values ...DataInfValue) {
+	if t.dims == nil && len(values) > 0 {
+		for _, id := range dimensionIDs {
+			if values[0].Dim(id) != "" {
+				t.dims = append(t.dims, id)
+			}
+		}
+	}
+
+	for _, v := range values {
+		r := TableRecord{
+			Dims:       make([]TableDim, len(t.dims)),
+			Unit:       v.Unit,
+			Annotation: v.Annotation,
+			Value:      v.Value,
+		}
+		for i, id := range t.dims {
+			d := t.dim(id, v.Dim(id))
+			if r.Unit == "" {
+				r.Unit = d.Unit
+			}
+			r.Dims[i] = d
+		}
+		t.Records = append(t.Records, r)
+	}
+}
+
+// id の分類事項の code のメタ情報を返す。
+func (t *Table) dim(id string, code string) TableDim {
+	d := TableDim{
+		ID:      id,
+		ObjName: t.objs[id].Name,
+		Code:    code,
+	}
+	if c, ok := t.classes[id][code]; ok {
+		d.Name = c.Name
+		d.Level = c.Level
+		d.Unit = c.Unit
+		d.ParentCode = c.ParentCode
+	}
+	return d
+}
+
+// 分類事項の ID を返します。
+func (t *Table) Dims() []string {
+	return t.dims
+}
+
+// 件数を返します。
+func (t *Table) Len() int {
+	return len(t.Records)
+}
+
+func (t *Table) dimIndex(id string) int {
+	for i, d := range t.dims {
+		if d == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// id の分類事項の列を返します。分類事項がない場合は nil を返します。
+func (t *Table) Column(id string) []TableDim {
+	i := t.dimIndex(id)
+	if i < 0 {
+		return nil
+	}
+
+	column := make([]TableDim, len(t.Records))
+	for n, r := range t.Records {
+		column[n] = r.Dims[i]
+	}
+	return column
+}
+
+// id の分類事項のコードの列を返します。
+func (t *Table) Codes(id string) []string {
+	column := t.Column(id)
+	if column == nil {
+		return nil
+	}
+
+	codes := make([]string, len(column))
+	for i, d := range column {
+		codes[i] = d.Code
+	}
+	return codes
+}
+
+// id の分類事項の名前の列を返します。
+func (t *Table) Names(id string) []string {
+	column := t.Column(id)
+	if column == nil {
+		return nil
+	}
+
+	names := make([]string, len(column))
+	for i, d := range column {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// 値の列を返します。
+func (t *Table) Values() []string {
+	values := make([]string, len(t.Records))
+	for i, r := range t.Records {
+		values[i] = r.Value
+	}
+	return values
+}
+
+// 単位の列を返します。
+func (t *Table) Units() []string {
+	units := make([]string, len(t.Records))
+	for i, r := range t.Records {
+		units[i] = r.Unit
+	}
+	return units
+}
@@ -0,0 +1,81 @@
+package core_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+	"github.com/itok01/e-stat-go/core/estattest"
+)
+
+func TestNewTable(t *testing.T) {
+	ctx := context.Background()
+	hc := mockHttpClient{}
+	ac := core.NewApiClient(&hc, core.CommonParams{})
+
+	data, err := ac.GetStatsData(ctx, core.ParamsGetStatsData{})
+	if err != nil {
+		t.Fatalf("GetStatsData() error = %v", err)
+	}
+
+	table := core.NewTable(data.DataList)
+
+	if table.Len() != len(data.DataList.Data.Value) {
+		t.Errorf("Len() = %d, want %d", table.Len(), len(data.DataList.Data.Value))
+	}
+	if want := []string{"tab", "cat01", "time"}; !reflect.DeepEqual(table.Dims(), want) {
+		t.Errorf("Dims() = %v, want %v", table.Dims(), want)
+	}
+
+	want := core.TableRecord{
+		Dims: []core.TableDim{
+			{ID: "tab", ObjName: "表章項目", Code: "11", Name: "金額", Unit: "10億円"},
+			{ID: "cat01", ObjName: "国内総生産_名目原系列", Code: "11", Name: "国内総生産(支出側)", Level: "1"},
+			{ID: "time", ObjName: "時間軸（四半期）", Code: "1994000103", Name: "1994年1～3月期", Level: "3", ParentCode: "1994010000"},
+		},
+		Unit:  "10億円",
+		Value: "123456.1",
+	}
+	if !reflect.DeepEqual(table.Records[0], want) {
+		t.Errorf("Records[0] = %v, want %v", table.Records[0], want)
+	}
+
+	if d, ok := table.Records[0].Dim("time"); !ok || d.Code != "1994000103" {
+		t.Errorf("Records[0].Dim(time) = %v, %v", d, ok)
+	}
+	if _, ok := table.Records[0].Dim("area"); ok {
+		t.Errorf("Records[0].Dim(area) ok = true, want false")
+	}
+
+	if got := table.Codes("time")[:2]; !reflect.DeepEqual(got, []string{"1994000103", "1994000406"}) {
+		t.Errorf("Codes(time) = %v", got)
+	}
+	if got := table.Names("cat01")[0]; got != "国内総生産(支出側)" {
+		t.Errorf("Names(cat01)[0] = %s", got)
+	}
+	if got := table.Values()[:2]; !reflect.DeepEqual(got, []string{"123456.1", "124896.6"}) {
+		t.Errorf("Values() = %v", got)
+	}
+	if table.Column("area") != nil {
+		t.Errorf("Column(area) = %v, want nil", table.Column("area"))
+	}
+}
+
+func TestReadTable(t *testing.T) {
+	ctx := context.Background()
+	hc := pagedHttpClient{values: []string{"2020", "2021", "2022"}}
+	ac := core.NewApiClient(estattest.ClientFunc(hc.do), core.CommonParams{})
+
+	table, err := core.ReadTable(ac.IterateStatsData(ctx, core.ParamsGetStatsData{}))
+	if err != nil {
+		t.Fatalf("ReadTable() error = %v", err)
+	}
+
+	if table.Info.ID != "0003109741" {
+		t.Errorf("Info.ID = %s", table.Info.ID)
+	}
+	if got := table.Codes("time"); !reflect.DeepEqual(got, []string{"2020", "2021", "2022"}) {
+		t.Errorf("Codes(time) = %v", got)
+	}
+}
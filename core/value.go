@@ -0,0 +1,146 @@
+package core
+
+import (
+	"math/big"
+	"strings"
+)
+
+// 特殊文字の置換 (ParamsGetStatsData.ReplaceSpChar)
+const (
+	// 置換しない (省略値)
+	ReplaceSpCharNone = 0
+
+	// 0 に置換する
+	ReplaceSpCharZero = 1
+
+	// NULL (空文字列) に置換する
+	ReplaceSpCharNull = 2
+
+	// "NA" に置換する
+	ReplaceSpCharNA = 3
+)
+
+// 統計データの値を数値に変換したもの
+type Number struct {
+	// 数値に変換できた場合は true
+	Valid bool
+
+	Float float64
+	Rat   *big.Rat
+
+	// 数値でない場合の特殊文字 ("-"、"***" など) と、NOTE による説明
+	Char   string
+	Reason string
+
+	Unit string
+
+	// ANNOTATION による注釈。対応する注釈がない場合は annotation 属性の値
+	Annotation string
+}
+
+// 欠損値かどうか
+func (n Number) Missing() bool {
+	return !n.Valid
+}
+
+// 統計データの値を Number に変換します。
+type ValueParser struct {
+	replaceSpChar int
+	notes         map[string]string
+	annotations   map[string]string
+	units         []map[string]string
+	dims          []string
+}
+
+// class と data の NOTE、ANNOTATION を使って値を変換する ValueParser を返します。
+//
+// replaceSpChar にはリクエストの ReplaceSpChar を指定して下さい。
+// ReplaceSpCharZero の場合、特殊文字は 0 と区別できません。
+func NewValueParser(class ClassInf, data DataInf, replaceSpChar int) *ValueParser {
+	p := &ValueParser{
+		replaceSpChar: replaceSpChar,
+		notes:         make(map[string]string, len(data.Note)),
+		annotations:   make(map[string]string, len(data.Annotation)),
+	}
+	for _, note := range data.Note {
+		p.notes[note.Char] = note.Note
+	}
+	for _, annotation := range data.Annotation {
+		p.annotations[annotation.Target] = annotation.Annotation
+	}
+	for _, obj := range class.ClassObj {
+		units := map[string]string{}
+		for _, c := range obj.Class {
+			if c.Unit != "" {
+				units[c.Code] = c.Unit
+			}
+		}
+		if len(units) > 0 {
+			p.dims = append(p.dims, obj.ID)
+			p.units = append(p.units, units)
+		}
+	}
+	return p
+}
+
+// 値を変換します。
+//
+// 単位は unit 属性、なければ分類事項の単位を使います。
+func (p *ValueParser) Parse(v DataInfValue) Number {
+	unit := v.Unit
+	for i := 0; unit == "" && i < len(p.dims); i++ {
+		unit = p.units[i][v.Dim(p.dims[i])]
+	}
+	return p.parse(v.Value, unit, v.Annotation)
+}
+
+// Table のレコードの値を変換します。
+func (p *ValueParser) ParseRecord(r TableRecord) Number {
+	return p.parse(r.Value, r.Unit, r.Annotation)
+}
+
+func (p *ValueParser) parse(value string, unit string, annotation string) Number {
+	n := Number{Unit: unit, Annotation: annotation}
+	if text, ok := p.annotations[annotation]; ok {
+		n.Annotation = text
+	}
+
+	value = strings.TrimSpace(value)
+	if reason, ok := p.notes[value]; ok {
+		n.Char = value
+		n.Reason = reason
+		return n
+	}
+	if (p.replaceSpChar == ReplaceSpCharNull && value == "") || (p.replaceSpChar == ReplaceSpCharNA && value == "NA") {
+		n.Char = value
+		return n
+	}
+
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		// NOTE にない特殊文字
+		n.Char = value
+		return n
+	}
+
+	n.Valid = true
+	n.Rat = r
+	n.Float, _ = r.Float64()
+	return n
+}
+
+// ValueParser を返します。
+func (t *Table) ValueParser(replaceSpChar int) *ValueParser {
+	return NewValueParser(t.Class, DataInf{Note: t.Note, Annotation: t.Annotation}, replaceSpChar)
+}
+
+// 値を数値に変換した列を返します。
+func (t *Table) Numbers(replaceSpChar int) []Number {
+	p := t.ValueParser(replaceSpChar)
+
+	numbers := make([]Number, len(t.Records))
+	for i, r := range t.Records {
+		numbers[i] = p.ParseRecord(r)
+	}
+	return numbers
+}
@@ -0,0 +1,116 @@
+package core_test
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/itok01/e-stat-go/core"
+)
+
+func TestValueParser(t *testing.T) {
+	class := core.ClassInf{
+		ClassObj: []core.ClassObj{
+			{ID: "tab", Class: []core.ClassObjClass{{Code: "01", Unit: "人"}, {Code: "02"}}},
+			{ID: "cat01", Class: []core.ClassObjClass{{Code: "001", Unit: "世帯"}}},
+		},
+	}
+	data := core.DataInf{
+		Note: []core.DataInfNote{
+			{Char: "-", Note: "該当数値なし"},
+			{Char: "***", Note: "秘匿"},
+		},
+		Annotation: []core.DataInfAnnotation{
+			{Target: "1", Annotation: "速報値"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		replaceSpChar int
+		arg           core.DataInfValue
+		want          core.Number
+	}{
+		{
+			name: "Integer",
+			arg:  core.DataInfValue{Tab: "01", Value: "1234"},
+			want: core.Number{Valid: true, Float: 1234, Rat: big.NewRat(1234, 1), Unit: "人"},
+		},
+		{
+			name: "Decimal",
+			arg:  core.DataInfValue{Tab: "01", Unit: "千人", Value: "-0.25"},
+			want: core.Number{Valid: true, Float: -0.25, Rat: big.NewRat(-1, 4), Unit: "千人"},
+		},
+		{
+			name: "UnitFromCategory",
+			arg:  core.DataInfValue{Tab: "02", Cat01: "001", Value: "3"},
+			want: core.Number{Valid: true, Float: 3, Rat: big.NewRat(3, 1), Unit: "世帯"},
+		},
+		{
+			name: "Note",
+			arg:  core.DataInfValue{Tab: "01", Value: "***"},
+			want: core.Number{Char: "***", Reason: "秘匿", Unit: "人"},
+		},
+		{
+			name: "UnknownChar",
+			arg:  core.DataInfValue{Tab: "02", Value: "…"},
+			want: core.Number{Char: "…"},
+		},
+		{
+			name: "Annotation",
+			arg:  core.DataInfValue{Tab: "02", Annotation: "1", Value: "-"},
+			want: core.Number{Char: "-", Reason: "該当数値なし", Annotation: "速報値"},
+		},
+		{
+			name:          "ReplaceSpCharNull",
+			replaceSpChar: core.ReplaceSpCharNull,
+			arg:           core.DataInfValue{Tab: "02", Value: ""},
+			want:          core.Number{},
+		},
+		{
+			name:          "ReplaceSpCharNA",
+			replaceSpChar: core.ReplaceSpCharNA,
+			arg:           core.DataInfValue{Tab: "02", Value: "NA"},
+			want:          core.Number{Char: "NA"},
+		},
+		{
+			name:          "ReplaceSpCharZero",
+			replaceSpChar: core.ReplaceSpCharZero,
+			arg:           core.DataInfValue{Tab: "02", Value: "0"},
+			want:          core.Number{Valid: true, Float: 0, Rat: new(big.Rat)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := core.NewValueParser(class, data, tt.replaceSpChar).Parse(tt.arg)
+			if (got.Rat == nil) != (tt.want.Rat == nil) || got.Rat != nil && got.Rat.Cmp(tt.want.Rat) != 0 {
+				t.Errorf("Parse().Rat = %v, want %v", got.Rat, tt.want.Rat)
+			}
+			got.Rat, tt.want.Rat = nil, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+			if got.Missing() == tt.want.Valid {
+				t.Errorf("Missing() = %v, want %v", got.Missing(), !tt.want.Valid)
+			}
+		})
+	}
+}
+
+func TestTableNumbers(t *testing.T) {
+	ctx := context.Background()
+	hc := mockHttpClient{}
+	ac := core.NewApiClient(&hc, core.CommonParams{})
+
+	data, err := ac.GetStatsData(ctx, core.ParamsGetStatsData{})
+	if err != nil {
+		t.Fatalf("GetStatsData() error = %v", err)
+	}
+
+	numbers := core.NewTable(data.DataList).Numbers(core.ReplaceSpCharNone)
+	if n := numbers[0]; !n.Valid || n.Float != 123456.1 || n.Unit != "10億円" {
+		t.Errorf("Numbers()[0] = %+v", n)
+	}
+}